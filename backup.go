@@ -0,0 +1,97 @@
+package snakeLoggerFile
+
+import (
+	"errors"
+	"fmt"
+)
+
+// backupQueueSize is how many entries can wait for the backup directory
+const backupQueueSize = 1024
+
+// backupSink mirrors the log files into a second directory
+// it writes from its own goroutine so a slow mount never holds up the
+// primary files, when it falls behind entries are dropped from the backup only
+// it is a copy of the log on disk, so it keeps writing files under
+// SetOutput and SetOutputFactory, only SetDiscard stops it
+type backupSink struct {
+	files *fileSink
+	queue chan backupEntry
+	done  chan struct{}
+}
+
+// backupEntry is an entry waiting for the backup with the primary's
+// filename builder at the time it was written, so both pick the same file
+type backupEntry struct {
+	l      LogData
+	nameFn func(LogData) string
+}
+
+func newBackupSink(dir string) *backupSink {
+	b := &backupSink{
+		files: newFileSink(),
+		queue: make(chan backupEntry, backupQueueSize),
+		done:  make(chan struct{}),
+	}
+	b.files.fixedDir = dir
+	go b.run()
+	return b
+}
+
+// write queues l to go in the file nameFn picks, it never blocks
+func (b *backupSink) write(l LogData, nameFn func(LogData) string) error {
+	select {
+	case b.queue <- backupEntry{l: l, nameFn: nameFn}:
+		return nil
+	default:
+		return errors.New("backup dir queue full, entry dropped")
+	}
+}
+
+func (b *backupSink) run() {
+	defer close(b.done)
+	for e := range b.queue {
+		// only this goroutine writes to the backup's files
+		b.files.mu.Lock()
+		b.files.nameFn = e.nameFn
+		b.files.mu.Unlock()
+		if err := b.files.Write(e.l); err != nil {
+			recordError(fmt.Errorf("backup dir: %w", err))
+		}
+		if len(b.queue) == 0 {
+			if err := b.files.Flush(); err != nil {
+				recordError(fmt.Errorf("backup dir: %w", err))
+			}
+		}
+	}
+	b.files.mu.Lock()
+	if err := b.files.closeAll(); err != nil {
+		recordError(fmt.Errorf("backup dir: %w", err))
+	}
+	b.files.mu.Unlock()
+}
+
+// stop lets the backup finish what is queued
+func (b *backupSink) stop() {
+	close(b.queue)
+	<-b.done
+}
+
+// SetBackupDir writes a copy of every log file line into dir as well,
+// in the same files SetFilenameBuilder picks for the log directory.
+// with SetOutput or SetOutputFactory it still writes files, so there is
+// a copy on disk wherever the main output goes
+// a failure there is recorded in Health but never affects the main files
+// an empty dir turns the backup off
+func SetBackupDir(dir string) {
+	var b *backupSink
+	if dir != "" {
+		b = newBackupSink(dir)
+	}
+	defaultFileSink.mu.Lock()
+	old := defaultFileSink.backup
+	defaultFileSink.backup = b
+	defaultFileSink.mu.Unlock()
+	if old != nil {
+		old.stop()
+	}
+}
@@ -0,0 +1,40 @@
+package snakeLoggerFile
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestBackupDirFollowsFilenameBuilder(t *testing.T) {
+	fsys := useMemFS(t)
+	SetFilenameBuilder(func(l LogData) string { return "games/" + l.SnakeName + ".log" })
+	defer SetFilenameBuilder(nil)
+	SetBackupDir("/backup")
+	defer SetBackupDir("")
+
+	l := NewLogger("debug").ToSnake("bk")
+	l.InfoSync("to the files")
+	var out bytes.Buffer
+	SetOutput(&out)
+	l.InfoSync("to the output")
+	SetOutput(nil)
+	// stopping the backup writes out what it has queued
+	SetBackupDir("")
+
+	if lines := readLog(t, fsys, "games/bk.log"); countContaining(lines, "to the files") != 1 {
+		t.Errorf("games/bk.log = %q", lines)
+	}
+	if !strings.Contains(out.String(), "to the output") {
+		t.Errorf("output = %q", out.String())
+	}
+	b, err := fsys.ReadFile("/backup/games/bk.log")
+	if err != nil {
+		t.Fatalf("the backup didn't use the filename builder: %v, files %v", err, fsys.Files())
+	}
+	for _, msg := range []string{"to the files", "to the output"} {
+		if strings.Count(string(b), msg) != 1 {
+			t.Errorf("backup games/bk.log = %q, want %q once", b, msg)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package snakeLoggerFile
+
+import (
+	"io/ioutil"
+	"testing"
+	"time"
+)
+
+func benchEntry() LogData {
+	return NewLogger("debug").ToSnake("bench").ForTurn(12).
+		WithField("move", "up").newEntry(InfoLevel, "moving", time.Now(), nil)
+}
+
+func BenchmarkInfo(b *testing.B) {
+	useMemFS(b)
+	l := NewLogger("debug").ToSnake("bench")
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.Info("moving up")
+	}
+	Sync()
+}
+
+func BenchmarkInfoWithFields(b *testing.B) {
+	useMemFS(b)
+	l := NewLogger("debug").ToSnake("bench").WithField("move", "up")
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.Info("moving")
+	}
+	Sync()
+}
+
+func BenchmarkInfoParallel(b *testing.B) {
+	useMemFS(b)
+	l := NewLogger("debug").ToSnake("bench")
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			l.Info("moving up")
+		}
+	})
+	Sync()
+}
+
+func BenchmarkFileSinkWrite(b *testing.B) {
+	useMemFS(b)
+	fs := newFileSink()
+	m := benchEntry()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := fs.Write(m); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkWriterSink(b *testing.B) {
+	ws := NewWriterSink(ioutil.Discard)
+	m := benchEntry()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ws.Write(m)
+	}
+}
+
+func BenchmarkString(b *testing.B) {
+	m := benchEntry()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = m.String()
+	}
+}
+
+func BenchmarkBytes(b *testing.B) {
+	m := benchEntry()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = m.Bytes()
+	}
+}
@@ -0,0 +1,79 @@
+package snakeLoggerFile
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"time"
+)
+
+// BoardHashField is the field name analytics use to match up positions
+const BoardHashField = "board_hash"
+
+// Board logs the game board at debug level
+// cells is indexed [y][x] with y=0 at the bottom like the battlesnake api,
+// it is rendered top row first, empty cells (0) show as '.'
+// the rows are attached as the "board" field so the message stays short
+func (s *SnakeLogger) Board(turn int, width, height int, cells [][]rune) {
+	if !s.enabled(DebugLevel) {
+		return
+	}
+	now := time.Now()
+	rows := renderBoard(width, height, cells)
+	fields := map[string]interface{}{
+		"board":  rows,
+		"width":  width,
+		"height": height,
+	}
+	if origin := originFields(); origin != nil {
+		fields = mergeFields(fields, origin)
+	}
+	c := s.ForTurn(turn)
+	c.send(DebugLevel, fmt.Sprintf("board %dx%d", width, height), now, fields)
+}
+
+// renderBoard turns cells into one string per row, top row first
+// a negative width or height draws as 0
+func renderBoard(width, height int, cells [][]rune) []string {
+	if width < 0 {
+		width = 0
+	}
+	if height < 0 {
+		height = 0
+	}
+	rows := make([]string, 0, height)
+	for y := height - 1; y >= 0; y-- {
+		row := make([]rune, width)
+		for x := 0; x < width; x++ {
+			row[x] = '.'
+			if y < len(cells) && x < len(cells[y]) && cells[y][x] != 0 {
+				row[x] = cells[y][x]
+			}
+		}
+		rows = append(rows, string(row))
+	}
+	return rows
+}
+
+// BoardHash is a short stable hash of a board, the same cells
+// (as Board would draw them) always give the same hash, across games and runs
+func BoardHash(width, height int, cells [][]rune) string {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%dx%d", width, height)
+	for _, row := range renderBoard(width, height, cells) {
+		h.Write([]byte{'\n'})
+		h.Write([]byte(row))
+	}
+	return strconv.FormatUint(h.Sum64(), 16)
+}
+
+// WithBoardHash returns a child logger that adds a board_hash field to every entry
+// pass a hash from BoardHash, or one worked out elsewhere
+func (s *SnakeLogger) WithBoardHash(hash string) *SnakeLogger {
+	return s.WithField(BoardHashField, hash)
+}
+
+// WithBoard is WithBoardHash with the hash worked out from the board
+func (s *SnakeLogger) WithBoard(width, height int, cells [][]rune) *SnakeLogger {
+	return s.WithBoardHash(BoardHash(width, height, cells))
+}
@@ -0,0 +1,29 @@
+package snakeLoggerFile
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRenderBoard(t *testing.T) {
+	cells := [][]rune{{'a', 0}, {0, 'b'}}
+	tests := []struct {
+		name          string
+		width, height int
+		want          []string
+	}{
+		{"whole board", 2, 2, []string{".b", "a."}},
+		{"bigger than the cells", 3, 3, []string{"...", ".b.", "a.."}},
+		{"negative width", -1, 2, []string{"", ""}},
+		{"negative height", 2, -3, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := renderBoard(tt.width, tt.height, cells); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("renderBoard(%d, %d) = %q, want %q", tt.width, tt.height, got, tt.want)
+			}
+		})
+	}
+	// used to panic making a slice of negative length
+	BoardHash(-1, 3, nil)
+}
@@ -0,0 +1,54 @@
+package snakeLoggerFile
+
+import (
+	"fmt"
+	"os"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	breakerThreshold int32
+	// breakerCooldown is a time.Duration
+	breakerCooldown int64
+)
+
+// SetCircuitBreaker stops writing to a sink for cooldown once threshold
+// writes to it in a row have failed, like the files on a full disk,
+// instead of failing (and printing) on every entry
+// one notice is printed to stderr each time a sink is paused, entries that arrive
+// while it is paused are dropped for that sink and counted in Health
+// after the cooldown the next entry is tried, one more failure pauses it again
+// a threshold of 0 or less (the default) turns it off
+func SetCircuitBreaker(threshold int, cooldown time.Duration) {
+	atomic.StoreInt64(&breakerCooldown, int64(cooldown))
+	atomic.StoreInt32(&breakerThreshold, int32(threshold))
+}
+
+// breaker is the circuit breaker state for one sink
+// it is only used from the writer goroutine, so it needs no locking
+type breaker struct {
+	failures  int
+	openUntil time.Time
+}
+
+// allow says if the sink can be written to now
+func (b *breaker) allow(now time.Time) bool {
+	return now.After(b.openUntil)
+}
+
+// result records how a write went, and pauses the sink if it has failed too often
+func (b *breaker) result(err error, now time.Time, s Sink) {
+	if err == nil {
+		b.failures = 0
+		return
+	}
+	b.failures++
+	threshold := int(atomic.LoadInt32(&breakerThreshold))
+	if threshold <= 0 || b.failures < threshold {
+		return
+	}
+	cooldown := time.Duration(atomic.LoadInt64(&breakerCooldown))
+	b.openUntil = now.Add(cooldown)
+	fmt.Fprintf(os.Stderr, "snakeLogger: %d writes in a row to %T failed, pausing it for %s\n", b.failures, s, cooldown)
+}
@@ -0,0 +1,87 @@
+package snakeLoggerFile
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// diverting counts the captures started with StartCaptureOnly that are running
+var diverting int32
+
+// Capture collects the entries written between StartCapture and Stop
+// it is global, it gets entries from every logger and goroutine,
+// not just the ones doing the operation being captured
+type Capture struct {
+	mu      sync.Mutex
+	entries []LogData
+	lines   []byte
+	// diverted is set once the writer counts the capture in diverting
+	diverted bool
+	remove   func()
+	once     sync.Once
+}
+
+// StartCapture starts collecting every entry the writer handles,
+// the files and sinks keep getting them as normal
+func StartCapture() *Capture {
+	return startCapture(false)
+}
+
+// StartCaptureOnly is StartCapture, but the entries go only to the capture,
+// no files or sinks, until it is stopped. Entries already queued when it is
+// called still go to the files. It must not be called from a sink or observer
+func StartCaptureOnly() *Capture {
+	return startCapture(true)
+}
+
+func startCapture(divert bool) *Capture {
+	c := &Capture{}
+	if !divert {
+		c.remove = AddObserver(c.add)
+		return c
+	}
+	// start on the writer, so what was queued before the call isn't diverted
+	c.remove = func() {}
+	runOnWriter(func() {
+		c.remove = AddObserver(c.add)
+		atomic.AddInt32(&diverting, 1)
+		c.diverted = true
+	})
+	return c
+}
+
+func (c *Capture) add(l LogData) {
+	c.mu.Lock()
+	c.entries = append(c.entries, l)
+	c.lines = appendFormat(c.lines, l)
+	c.mu.Unlock()
+}
+
+// Stop waits for the writer to get through what is already queued,
+// stops collecting and returns the entries formatted as they would be in
+// the files. Entries still being handed to the writer by their log call
+// may be missed. It must not be called from a sink or observer
+func (c *Capture) Stop() []byte {
+	c.once.Do(func() {
+		runOnWriter(func() {})
+		c.remove()
+		if c.diverted {
+			atomic.AddInt32(&diverting, -1)
+		}
+	})
+	return c.Bytes()
+}
+
+// Bytes is what has been captured so far, formatted
+func (c *Capture) Bytes() []byte {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]byte(nil), c.lines...)
+}
+
+// Entries is what has been captured so far
+func (c *Capture) Entries() []LogData {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]LogData(nil), c.entries...)
+}
@@ -0,0 +1,44 @@
+package snakeLoggerFile
+
+import (
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestCaptureOnlyLeavesQueuedEntries(t *testing.T) {
+	fsys := useMemFS(t)
+	l := NewLogger("debug").ToSnake("cap")
+
+	// hold the writer so "queued" is still waiting when the capture starts
+	release := make(chan struct{})
+	held := make(chan struct{})
+	go runOnWriter(func() {
+		close(held)
+		<-release
+	})
+	<-held
+	l.Info("queued")
+	started := make(chan *Capture)
+	go func() { started <- StartCaptureOnly() }()
+	// let StartCaptureOnly get going before the writer does
+	for len(writeChan) < 2 && atomic.LoadInt32(&diverting) == 0 {
+		runtime.Gosched()
+	}
+	close(release)
+	c := <-started
+	l.Info("captured")
+	out := string(c.Stop())
+
+	lines := readLog(t, fsys, "cap.log")
+	if countContaining(lines, "queued") != 1 || countContaining(lines, "captured") != 0 {
+		t.Errorf("cap.log = %q, want only the entry queued before the capture", lines)
+	}
+	if strings.Contains(out, "queued") || !strings.Contains(out, "captured") {
+		t.Errorf("capture = %q, want only the entry made during it", out)
+	}
+	if n := atomic.LoadInt32(&diverting); n != 0 {
+		t.Errorf("diverting = %d after Stop", n)
+	}
+}
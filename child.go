@@ -0,0 +1,35 @@
+package snakeLoggerFile
+
+// ToSnake returns a child logger that writes to another snake's file
+// the parent keeps its own name, so this is safe for one off calls:
+//
+//	logger.ToSnake("othersnake").Info("attributed to othersnake")
+func (s *SnakeLogger) ToSnake(name string) *SnakeLogger {
+	c := s.clone()
+	c.name = name
+	return c
+}
+
+// ForGame returns a child logger for game id, so games running at the same
+// time on one snake's logger don't share context
+// the parent and its other children keep their own id
+func (s *SnakeLogger) ForGame(id string) *SnakeLogger {
+	c := s.clone()
+	c.id = id
+	return c
+}
+
+// ForTurn returns a child logger at turn t, the parent keeps its turn
+func (s *SnakeLogger) ForTurn(t int) *SnakeLogger {
+	c := s.clone()
+	c.currentTurn = int64(t)
+	return c
+}
+
+// ForFunc returns a child logger that tags entries with function name,
+// the parent keeps its function
+func (s *SnakeLogger) ForFunc(name string) *SnakeLogger {
+	c := s.clone()
+	c.currentFunc = name
+	return c
+}
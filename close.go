@@ -0,0 +1,133 @@
+package snakeLoggerFile
+
+import (
+	"context"
+	"sync"
+)
+
+var (
+	// closeMu orders beginSend against Close and Flush, so no send starts
+	// once Close is waiting and none joins a generation Flush is waiting on
+	closeMu sync.RWMutex
+	closed  bool
+	// inflight counts log calls and control calls still on their way to the writer
+	inflight sync.WaitGroup
+	// flushGen counts the sends begun since the last Flush started, Flush
+	// swaps in a new one and waits for the old one without holding closeMu
+	flushGen = new(sync.WaitGroup)
+	// writerDone is closed once the writer has finished, closeErr is only
+	// set before that
+	writerDone = make(chan struct{})
+	closeErr   error
+)
+
+// beginSend registers a send to the writer, false once Close has been called
+// every true must be matched by an endSend with the generation it gave
+func beginSend() (*sync.WaitGroup, bool) {
+	closeMu.RLock()
+	defer closeMu.RUnlock()
+	if closed {
+		return nil, false
+	}
+	inflight.Add(1)
+	flushGen.Add(1)
+	return flushGen, true
+}
+
+func endSend(gen *sync.WaitGroup) {
+	gen.Done()
+	inflight.Done()
+}
+
+// closer is a sink that holds something open
+type closer interface {
+	Close() error
+}
+
+// Close waits for every log call already made to be written, then
+// flushes and closes every sink (the log files last) and stops the writer
+// defer it in main so the last turn's lines aren't lost on exit
+// logging after Close does nothing, and calling it again returns straight away
+func Close() error {
+	flushNotices()
+	closeMu.Lock()
+	if closed {
+		closeMu.Unlock()
+		<-writerDone
+		return closeErr
+	}
+	closed = true
+	closeMu.Unlock()
+
+	inflight.Wait()
+	close(writeChan)
+	<-writerDone
+	return closeErr
+}
+
+// Flush waits for every log call already made to be written and then flushes
+// every sink, like Close but the logger keeps working afterwards
+// it returns ctx.Err() if ctx is done first, the flush still finishes later.
+// log calls made while Flush is waiting go ahead, they are not waited for
+func Flush(ctx context.Context) error {
+	done := make(chan error, 1)
+	go func() {
+		flushNotices()
+		closeMu.Lock()
+		if closed {
+			closeMu.Unlock()
+			<-writerDone
+			done <- closeErr
+			return
+		}
+		// sends from here on join a new generation, so nothing more is
+		// added to gen and it can be waited for without the lock, a log
+		// call held up behind the writer can't hold Flush up for good
+		gen := flushGen
+		flushGen = new(sync.WaitGroup)
+		closeMu.Unlock()
+		gen.Wait()
+		done <- Sync()
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// stopWriter is run by the writer once the channel is closed
+func stopWriter() {
+	flushRepeats("")
+	first := flushSinks()
+	sinksMu.RLock()
+	for i := len(sinks) - 1; i >= 0; i-- {
+		if c, ok := sinks[i].sink.(closer); ok {
+			if err := c.Close(); err != nil {
+				recordError(err)
+				if first == nil {
+					first = err
+				}
+			}
+		}
+	}
+	sinksMu.RUnlock()
+	closeErr = first
+	close(writerDone)
+}
+
+// Close implements closer, it closes every cached handle and stops
+// the backup and the compression sweeper
+func (fs *fileSink) Close() error {
+	fs.restartSweeper(false, RotationConfig{})
+	fs.mu.Lock()
+	err := fs.closeAll()
+	b := fs.backup
+	fs.backup = nil
+	fs.mu.Unlock()
+	if b != nil {
+		b.stop()
+	}
+	return err
+}
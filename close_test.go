@@ -0,0 +1,44 @@
+package snakeLoggerFile
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestFlushWithAnObserverLogging(t *testing.T) {
+	fsys := useMemFS(t)
+	l := NewLogger("debug").ToSnake("flush")
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	remove := AddObserver(func(m LogData) {
+		if m.Msg == "hold" {
+			close(entered)
+			<-release
+			// not something to do, but it must not hang Flush for good
+			l.Info("from the observer")
+		}
+	})
+	defer remove()
+
+	l.Info("hold")
+	<-entered
+	// a Sync stuck behind the observer is in flight while Flush waits
+	go Sync()
+	for QueueLen() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	flushed := make(chan error, 1)
+	go func() { flushed <- Flush(ctx) }()
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+
+	if err := <-flushed; err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	if lines := readLog(t, fsys, "flush.log"); countContaining(lines, "from the observer") != 1 {
+		t.Errorf("flush.log = %q", lines)
+	}
+}
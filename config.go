@@ -0,0 +1,162 @@
+package snakeLoggerFile
+
+import (
+	"math"
+	"sync/atomic"
+	"time"
+)
+
+// Configuration is a snapshot of the package level settings
+// a new package level setting should be added here and to Config as well
+type Configuration struct {
+	// BaseDir is where the log files are going, empty until the first write picks it
+	BaseDir   string
+	BackupDir string
+	// DirIndex is set with SetDirIndex, -1 when off
+	DirIndex     int
+	FileLevel    SnakeLoggerLevel
+	Formatter    Formatter
+	QueueSize    int
+	MaxOpenFiles int
+	FlushLevel   SnakeLoggerLevel
+	Rotation     RotationConfig
+	// Sinks counts the sinks added with AddSink, not the files
+	Sinks              int
+	UnixPrecision      UnixPrecision
+	DryRun             bool
+	Discard            bool
+	CollapseDuplicates bool
+	StackTraces        bool
+	Heartbeat          time.Duration
+	Overflow           OverflowPolicy
+	SequenceNumbers    bool
+	Sanitize           SanitizeMode
+	UTC                bool
+	MaxDirSize         int64
+	InvalidUTF8        UTF8Mode
+	// GlobalLevel is only in use when HasGlobalLevel, see SetGlobalLevel
+	GlobalLevel    SnakeLoggerLevel
+	HasGlobalLevel bool
+	// SnakeLevels are the SetSnakeLevel overrides, a copy
+	SnakeLevels   map[string]SnakeLoggerLevel
+	FlushInterval time.Duration
+	IdleTimeout   time.Duration
+	MaxFields     int
+	GenericLevel  SnakeLoggerLevel
+	GenericSource string
+	// RouteField and FileLayout say how the files are split, when
+	// CustomFilenames is set SetFilenameBuilder was given a builder of its own
+	RouteField      string
+	FileLayout      FileLayout
+	CustomFilenames bool
+	// BreakerThreshold is 0 while the circuit breaker is off
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+	WriteErrorPolicy WriteErrorPolicy
+	RecoverPanics    bool
+	// WriteTiming is the summary interval, 0 when off
+	WriteTiming      time.Duration
+	ReopenOnSIGHUP   bool
+	DeadlineFraction float64
+	AutoIndex        bool
+	OriginPackage    bool
+}
+
+// Config returns the settings the logger is running with right now
+// handy to log at startup when working out why logs end up somewhere
+func Config() Configuration {
+	c := Configuration{
+		Formatter:          currentFormatter(),
+		QueueSize:          QueueCap(),
+		UnixPrecision:      UnixPrecision(atomic.LoadInt32(&unixPrecision)),
+		DryRun:             isDryRun(),
+		CollapseDuplicates: atomic.LoadInt32(&collapseDuplicates) == 1,
+		StackTraces:        atomic.LoadInt32(&captureStacks) == 1,
+		Overflow:           OverflowPolicy(atomic.LoadInt32(&overflowPolicy)),
+		Sanitize:           SanitizeMode(atomic.LoadInt32(&sanitizeMode)),
+		UTC:                atomic.LoadInt32(&utcTimestamps) == 1,
+		InvalidUTF8:        UTF8Mode(atomic.LoadInt32(&utf8Mode)),
+		FlushInterval:      time.Duration(atomic.LoadInt64(&flushInterval)),
+		MaxFields:          int(atomic.LoadInt32(&maxFields)),
+		GenericLevel:       genericLevel(),
+		BreakerThreshold:   int(atomic.LoadInt32(&breakerThreshold)),
+		BreakerCooldown:    time.Duration(atomic.LoadInt64(&breakerCooldown)),
+		RecoverPanics:      atomic.LoadInt32(&recoverPanics) == 1,
+		DeadlineFraction:   math.Float64frombits(atomic.LoadUint64(&deadlineFraction)),
+		AutoIndex:          atomic.LoadInt32(&autoIndex) == 1,
+		OriginPackage:      atomic.LoadInt32(&captureOrigin) == 1,
+	}
+	c.FileLevel, _ = SinkLevel(defaultFileSink)
+	if g := atomic.LoadInt32(&globalLevel); g >= 0 {
+		c.GlobalLevel, c.HasGlobalLevel = SnakeLoggerLevel(g), true
+	}
+	snakeLevelsMu.Lock()
+	if m := copySnakeLevels(); len(m) > 0 {
+		c.SnakeLevels = m
+	}
+	snakeLevelsMu.Unlock()
+
+	genericSourceMu.RLock()
+	c.GenericSource = genericSource
+	genericSourceMu.RUnlock()
+
+	errorPolicyMu.RLock()
+	c.WriteErrorPolicy = errorPolicy
+	errorPolicyMu.RUnlock()
+
+	sinksMu.RLock()
+	for _, se := range sinks {
+		if !sameSink(se.sink, defaultFileSink) {
+			c.Sinks++
+		}
+	}
+	sinksMu.RUnlock()
+
+	defaultFileSink.mu.Lock()
+	c.BaseDir = defaultFileSink.basedir
+	c.DirIndex = defaultFileSink.dirIndex
+	c.MaxOpenFiles = defaultFileSink.maxOpen
+	c.FlushLevel = defaultFileSink.flushLevel
+	c.Rotation = defaultFileSink.rotation
+	c.Discard = defaultFileSink.discard
+	c.SequenceNumbers = defaultFileSink.seqs != nil
+	c.RouteField = defaultFileSink.routeField
+	c.FileLayout = defaultFileSink.layout
+	c.CustomFilenames = defaultFileSink.nameFn != nil && c.RouteField == "" && c.FileLayout == PerSnake
+	if defaultFileSink.backup != nil {
+		c.BackupDir = defaultFileSink.backup.files.fixedDir
+	}
+	defaultFileSink.mu.Unlock()
+
+	pruneMu.Lock()
+	c.MaxDirSize = pruneBudget
+	pruneMu.Unlock()
+
+	heartbeatMu.Lock()
+	c.Heartbeat = heartbeatEvery
+	heartbeatMu.Unlock()
+
+	idleMu.Lock()
+	c.IdleTimeout = idleTimeout
+	idleMu.Unlock()
+
+	timingMu.Lock()
+	c.WriteTiming = timingEvery
+	timingMu.Unlock()
+
+	hupMu.Lock()
+	c.ReopenOnSIGHUP = hupChan != nil
+	hupMu.Unlock()
+	return c
+}
+
+// StrictMode sets everything up for machine ingestion in one call:
+// JSON lines (one entry per line, keys and fields in a fixed order,
+// messages escaped by the encoder), UTC timestamps and control
+// characters escaped. The package writes no banner either way.
+// Each setting can still be changed afterwards with its own setter
+func StrictMode() {
+	SetFormatter(JSONFormatter{})
+	SetUTC(true)
+	SetSanitize(SanitizeEscape)
+}
@@ -0,0 +1,71 @@
+package snakeLoggerFile
+
+import (
+	"testing"
+	"time"
+)
+
+func TestConfigReportsSettings(t *testing.T) {
+	SetGlobalLevel(WarnLevel)
+	defer ClearGlobalLevel()
+	SetSnakeLevel("loud", DebugLevel)
+	defer ClearSnakeLevel("loud")
+	SetFlushInterval(3 * time.Second)
+	defer SetFlushInterval(defaultFlushInterval)
+	SetIdleTimeout(time.Minute)
+	defer SetIdleTimeout(0)
+	SetMaxFields(8)
+	defer SetMaxFields(defaultMaxFields)
+	SetGenericLevel(InfoLevel)
+	defer SetGenericLevel(DebugLevel)
+	SetGenericSource("host-1")
+	defer SetGenericSource("")
+	SetRouteField("id")
+	defer SetRouteField("")
+	SetCircuitBreaker(3, time.Second)
+	defer SetCircuitBreaker(0, 0)
+	policy := WriteErrorPolicy{Retries: 2, Backoff: time.Millisecond, Stderr: true}
+	SetWriteErrorPolicy(policy)
+	defer SetWriteErrorPolicy(WriteErrorPolicy{})
+	SetRecoverPanics(false)
+	defer SetRecoverPanics(true)
+	SetWriteTiming(time.Hour)
+	defer SetWriteTiming(0)
+	SetReopenOnSIGHUP(true)
+	defer SetReopenOnSIGHUP(false)
+
+	c := Config()
+	if !c.HasGlobalLevel || c.GlobalLevel != WarnLevel {
+		t.Errorf("GlobalLevel = %v, %v", c.GlobalLevel, c.HasGlobalLevel)
+	}
+	if c.SnakeLevels["loud"] != DebugLevel || len(c.SnakeLevels) != 1 {
+		t.Errorf("SnakeLevels = %v", c.SnakeLevels)
+	}
+	if c.FlushInterval != 3*time.Second || c.IdleTimeout != time.Minute || c.MaxFields != 8 {
+		t.Errorf("FlushInterval, IdleTimeout, MaxFields = %v, %v, %d", c.FlushInterval, c.IdleTimeout, c.MaxFields)
+	}
+	if c.GenericLevel != InfoLevel || c.GenericSource != "host-1" {
+		t.Errorf("GenericLevel, GenericSource = %v, %q", c.GenericLevel, c.GenericSource)
+	}
+	if c.RouteField != "id" || c.FileLayout != PerSnake || c.CustomFilenames {
+		t.Errorf("RouteField, FileLayout, CustomFilenames = %q, %v, %v", c.RouteField, c.FileLayout, c.CustomFilenames)
+	}
+	if c.BreakerThreshold != 3 || c.BreakerCooldown != time.Second {
+		t.Errorf("breaker = %d, %v", c.BreakerThreshold, c.BreakerCooldown)
+	}
+	if c.WriteErrorPolicy != policy || c.RecoverPanics {
+		t.Errorf("WriteErrorPolicy, RecoverPanics = %+v, %v", c.WriteErrorPolicy, c.RecoverPanics)
+	}
+	if c.WriteTiming != time.Hour || !c.ReopenOnSIGHUP {
+		t.Errorf("WriteTiming, ReopenOnSIGHUP = %v, %v", c.WriteTiming, c.ReopenOnSIGHUP)
+	}
+
+	SetFileLayout(PerGame)
+	if c := Config(); c.FileLayout != PerGame || c.RouteField != "" || c.CustomFilenames {
+		t.Errorf("after SetFileLayout(PerGame): %v, %q, %v", c.FileLayout, c.RouteField, c.CustomFilenames)
+	}
+	SetFilenameBuilder(DefaultFilename)
+	if c := Config(); !c.CustomFilenames || c.FileLayout != PerSnake {
+		t.Errorf("after SetFilenameBuilder: %v, %v", c.CustomFilenames, c.FileLayout)
+	}
+}
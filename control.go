@@ -0,0 +1,152 @@
+package snakeLoggerFile
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"sync/atomic"
+)
+
+// SetLevel changes the level of this logger while it is in use
+// children made before the call keep the level they had
+func (s *SnakeLogger) SetLevel(level SnakeLoggerLevel) {
+	s.updateLogLevel(level)
+}
+
+// Level is the logger's own level, SetGlobalLevel and SetSnakeLevel may override it
+func (s *SnakeLogger) Level() SnakeLoggerLevel {
+	return s.getLogLevel()
+}
+
+var (
+	// globalLevel is a SnakeLoggerLevel, -1 when no global level is set
+	globalLevel int32 = -1
+
+	snakeLevelsMu sync.Mutex
+	// snakeLevels is a map[string]SnakeLoggerLevel, replaced and never changed
+	snakeLevels atomic.Value
+	// haveSnakeLevels saves the map lookup while there are none
+	haveSnakeLevels int32
+)
+
+// SetGlobalLevel makes every logger use level instead of its own,
+// to turn on debug everywhere mid-tournament without a redeploy
+// null loggers still drop everything, and a WithTemporaryLevel child keeps
+// its level until restored. ClearGlobalLevel goes back
+func SetGlobalLevel(level SnakeLoggerLevel) {
+	atomic.StoreInt32(&globalLevel, int32(level))
+}
+
+// ClearGlobalLevel puts every logger back on its own level
+func ClearGlobalLevel() {
+	atomic.StoreInt32(&globalLevel, -1)
+}
+
+// SetSnakeLevel makes every logger for snakeName use level, it wins over
+// SetGlobalLevel, so one misbehaving snake can be turned up on its own
+// like SetGlobalLevel it doesn't change a WithTemporaryLevel child
+func SetSnakeLevel(snakeName string, level SnakeLoggerLevel) {
+	snakeLevelsMu.Lock()
+	defer snakeLevelsMu.Unlock()
+	m := copySnakeLevels()
+	m[snakeName] = level
+	storeSnakeLevels(m)
+}
+
+// ClearSnakeLevel takes away the override from SetSnakeLevel
+func ClearSnakeLevel(snakeName string) {
+	snakeLevelsMu.Lock()
+	defer snakeLevelsMu.Unlock()
+	m := copySnakeLevels()
+	delete(m, snakeName)
+	storeSnakeLevels(m)
+}
+
+// copySnakeLevels is a copy of the overrides to change, snakeLevelsMu must be held
+func copySnakeLevels() map[string]SnakeLoggerLevel {
+	old, _ := snakeLevels.Load().(map[string]SnakeLoggerLevel)
+	m := make(map[string]SnakeLoggerLevel, len(old)+1)
+	for k, v := range old {
+		m[k] = v
+	}
+	return m
+}
+
+func storeSnakeLevels(m map[string]SnakeLoggerLevel) {
+	snakeLevels.Store(m)
+	var have int32
+	if len(m) > 0 {
+		have = 1
+	}
+	atomic.StoreInt32(&haveSnakeLevels, have)
+}
+
+// effectiveLevel is the level a logger named name at level own logs at
+func effectiveLevel(name string, own SnakeLoggerLevel) SnakeLoggerLevel {
+	if atomic.LoadInt32(&haveSnakeLevels) == 1 {
+		m, _ := snakeLevels.Load().(map[string]SnakeLoggerLevel)
+		if l, ok := m[name]; ok {
+			return l
+		}
+	}
+	if g := atomic.LoadInt32(&globalLevel); g >= 0 {
+		return SnakeLoggerLevel(g)
+	}
+	return own
+}
+
+// levelStatus is what LevelHandler reports
+type levelStatus struct {
+	Global string            `json:"global,omitempty"`
+	Snakes map[string]string `json:"snakes,omitempty"`
+}
+
+// LevelHandler is a small endpoint for changing levels at runtime, mount it
+// somewhere private like http.Handle("/debug/loglevel", LevelHandler())
+//
+//	GET                          the overrides in place, as JSON
+//	POST level=debug             SetGlobalLevel
+//	POST level=debug&snake=name  SetSnakeLevel
+//	DELETE [snake=name]          ClearGlobalLevel or ClearSnakeLevel
+func LevelHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		snake := r.FormValue("snake")
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPost, http.MethodPut:
+			level, err := ParseLevel(r.FormValue("level"))
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if snake != "" {
+				SetSnakeLevel(snake, level)
+			} else {
+				SetGlobalLevel(level)
+			}
+		case http.MethodDelete:
+			if snake != "" {
+				ClearSnakeLevel(snake)
+			} else {
+				ClearGlobalLevel()
+			}
+		default:
+			w.Header().Set("Allow", "GET, POST, PUT, DELETE")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		var st levelStatus
+		if g := atomic.LoadInt32(&globalLevel); g >= 0 {
+			st.Global = levelName(SnakeLoggerLevel(g))
+		}
+		m, _ := snakeLevels.Load().(map[string]SnakeLoggerLevel)
+		if len(m) > 0 {
+			st.Snakes = make(map[string]string, len(m))
+			for k, v := range m {
+				st.Snakes[k] = levelName(v)
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(st)
+	})
+}
@@ -0,0 +1,33 @@
+package snakeLoggerFile
+
+import "testing"
+
+func TestTemporaryLevelWinsOverOverrides(t *testing.T) {
+	tests := []struct {
+		name     string
+		override func()
+	}{
+		{"global", func() { SetGlobalLevel(ErrorLevel) }},
+		{"snake", func() { SetSnakeLevel("test", ErrorLevel) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.override()
+			defer ClearGlobalLevel()
+			defer ClearSnakeLevel("test")
+
+			l := NewTestLogger()
+			tmp, restore := l.WithTemporaryLevel(DebugLevel)
+			tmp.Debug("while debugging")
+			tmp.ForFunc("inner").Debug("from a child")
+			l.Debug("parent follows the override")
+			restore()
+			tmp.Debug("after restore")
+
+			got := l.FilterByLevel(DebugLevel)
+			if len(got) != 2 || got[0].Msg != "while debugging" || got[1].Msg != "from a child" {
+				t.Errorf("debug entries %+v, want only the two made before restore", got)
+			}
+		})
+	}
+}
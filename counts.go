@@ -0,0 +1,24 @@
+package snakeLoggerFile
+
+import "sync/atomic"
+
+// levelCounts is indexed by SnakeLoggerLevel
+var levelCounts [256]uint64
+
+// countLevel adds one to the count for level, called once an entry passes the level gate
+func countLevel(level SnakeLoggerLevel) {
+	atomic.AddUint64(&levelCounts[level], 1)
+}
+
+// Counts is how many entries at each level have been logged this run
+// entries dropped by a logger's level are not counted, Raw lines aren't either
+func Counts() map[SnakeLoggerLevel]uint64 {
+	t := levels()
+	res := make(map[SnakeLoggerLevel]uint64, len(t.order))
+	for _, l := range t.order {
+		if l != NullLevel {
+			res[l] = atomic.LoadUint64(&levelCounts[l])
+		}
+	}
+	return res
+}
@@ -0,0 +1,38 @@
+package snakeLoggerFile
+
+import (
+	"fmt"
+	"math"
+	"sync/atomic"
+	"time"
+)
+
+// defaultDeadlineFraction warns once 80% of the move budget is used
+const defaultDeadlineFraction = 0.8
+
+// deadlineFraction holds the float64 bits of the fraction
+var deadlineFraction = math.Float64bits(defaultDeadlineFraction)
+
+// SetDeadlineFraction sets how much of the budget DeadlineWarn lets
+// a move use before it warns, 0.8 by default
+func SetDeadlineFraction(f float64) {
+	atomic.StoreUint64(&deadlineFraction, math.Float64bits(f))
+}
+
+// DeadlineWarn logs a warning with used_ms, budget_ms and remaining_ms
+// fields when used is past the deadline fraction of budget
+// and does nothing otherwise, call it at the end of each move
+func (s *SnakeLogger) DeadlineWarn(used, budget time.Duration) {
+	if !s.enabled(WarnLevel) {
+		return
+	}
+	f := math.Float64frombits(atomic.LoadUint64(&deadlineFraction))
+	if float64(used) <= f*float64(budget) {
+		return
+	}
+	s.logFields(WarnLevel, fmt.Sprintf("move took %s of %s budget", used, budget), map[string]interface{}{
+		"used_ms":      used.Milliseconds(),
+		"budget_ms":    budget.Milliseconds(),
+		"remaining_ms": (budget - used).Milliseconds(),
+	})
+}
@@ -0,0 +1,88 @@
+package snakeLoggerFile
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+var collapseDuplicates int32
+
+// SetCollapseDuplicates works like uniq on each log file
+// when on, an entry with the same severity, function and message as the
+// one just written to that file is dropped, and once a different entry
+// shows up a "last message repeated N times" line is written first
+// Sync, Flush, FlushFor and Close write the line too, so a run of
+// repeats at the end isn't lost
+// turning it off writes any line still owed and forgets the last entries,
+// so it must not be called that way from a sink or observer
+func SetCollapseDuplicates(on bool) {
+	if on {
+		atomic.StoreInt32(&collapseDuplicates, 1)
+		return
+	}
+	runOnWriter(func() {
+		atomic.StoreInt32(&collapseDuplicates, 0)
+		flushRepeats("")
+		lastWritten = map[string]*dupState{}
+	})
+	// the writer doesn't run anything once Close is done
+	atomic.StoreInt32(&collapseDuplicates, 0)
+}
+
+// dupState is the last entry written to one file
+type dupState struct {
+	last     LogData
+	repeated int
+}
+
+// lastWritten is keyed by the file name in the log directory
+// it is only used by the writer goroutine, so it has no lock
+var lastWritten = map[string]*dupState{}
+
+// collapse appends what should actually be written for m to dst
+// that is nothing for a repeat, or a repeat notice followed by m
+func collapse(dst []LogData, m LogData) []LogData {
+	if atomic.LoadInt32(&collapseDuplicates) == 0 {
+		return append(dst, m)
+	}
+	key := defaultFileSink.fileKey(m)
+	st, ok := lastWritten[key]
+	if !ok {
+		lastWritten[key] = &dupState{last: m}
+		return append(dst, m)
+	}
+	if st.last.Sev == m.Sev && st.last.Function == m.Function && st.last.Msg == m.Msg {
+		st.repeated++
+		return dst
+	}
+	res := dst
+	if st.repeated > 0 {
+		res = append(res, st.notice(m.Timestamp, m.UnixTimeStamp))
+	}
+	st.last = m
+	return append(res, m)
+}
+
+// notice is the repeat line for st, stamped with the given time
+// it starts a new count
+func (st *dupState) notice(timestamp string, unix int64) LogData {
+	n := st.last
+	n.Msg = fmt.Sprintf("last message repeated %d times", st.repeated)
+	n.Timestamp = timestamp
+	n.UnixTimeStamp = unix
+	st.repeated = 0
+	return n
+}
+
+// flushRepeats writes the repeat line still owed for the file key,
+// or for every file when key is empty, it must only run on the writer
+func flushRepeats(key string) {
+	now := time.Now()
+	for k, st := range lastWritten {
+		if st.repeated == 0 || (key != "" && k != key) {
+			continue
+		}
+		safeDispatch(st.notice(formatTimestamp(now), unixTime(now)))
+	}
+}
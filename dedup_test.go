@@ -0,0 +1,60 @@
+package snakeLoggerFile
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCollapseDuplicatesPerFile(t *testing.T) {
+	fsys := useMemFS(t)
+	SetCollapseDuplicates(true)
+	defer SetCollapseDuplicates(false)
+	SetRouteField("id")
+	defer SetRouteField("")
+
+	// two snakes in one game share game1.log, so they share the count
+	a := NewLogger("debug").ToSnake("a").ForGame("game1")
+	b := NewLogger("debug").ToSnake("b").ForGame("game1")
+	a.Info("same")
+	b.Info("same")
+	a.Info("other")
+
+	lines := readLog(t, fsys, "game1.log")
+	if len(lines) != 3 || countContaining(lines, "same") != 1 ||
+		countContaining(lines, "last message repeated 1 times") != 1 || countContaining(lines, "other") != 1 {
+		t.Errorf("game1.log = %q", lines)
+	}
+}
+
+func TestCollapseDuplicatesNoticeOnFlush(t *testing.T) {
+	fsys := useMemFS(t)
+	SetCollapseDuplicates(true)
+	defer SetCollapseDuplicates(false)
+
+	l := NewLogger("debug").ToSnake("dup")
+	for i := 0; i < 3; i++ {
+		l.Info("again")
+	}
+	// readLog syncs, which writes the notice still owed
+	lines := readLog(t, fsys, "dup.log")
+	if len(lines) != 2 || countContaining(lines, "last message repeated 2 times") != 1 {
+		t.Errorf("after Sync dup.log = %q", lines)
+	}
+
+	l.Info("again")
+	other := NewLogger("debug").ToSnake("other")
+	other.Info("again")
+	other.Info("again")
+	if err := FlushFor("dup"); err != nil {
+		t.Fatal(err)
+	}
+	// read without syncing, which would write every notice
+	b, _ := fsys.ReadFile("/logs/dup.log")
+	if strings.Count(string(b), "last message repeated 1 times") != 1 {
+		t.Errorf("after FlushFor dup.log = %q", b)
+	}
+	b, _ = fsys.ReadFile("/logs/other.log")
+	if strings.Contains(string(b), "repeated") {
+		t.Errorf("FlushFor(dup) wrote the notice for other.log: %q", b)
+	}
+}
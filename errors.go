@@ -0,0 +1,77 @@
+package snakeLoggerFile
+
+import (
+	"os"
+	"sync"
+	"time"
+)
+
+// WriteErrorPolicy is what happens when a sink fails to write an entry
+// the zero value records the error and drops the entry for that sink,
+// which is the default. The error is never fatal
+type WriteErrorPolicy struct {
+	// Retries is how many more times to try the write, on the writer
+	// goroutine, so keep it and Backoff small
+	Retries int
+	// Backoff is the wait before the first retry, it doubles each time
+	Backoff time.Duration
+	// Stderr writes the entry to stderr when the sink still failed
+	Stderr bool
+}
+
+// ErrorHandler is told about every entry a sink failed to write,
+// after the policy has had its go. It runs on the writer goroutine
+// and must not log through this package
+type ErrorHandler func(err error, l LogData)
+
+var (
+	errorPolicyMu sync.RWMutex
+	errorPolicy   WriteErrorPolicy
+	errorHandler  ErrorHandler
+)
+
+// SetWriteErrorPolicy changes what is done about failed writes
+func SetWriteErrorPolicy(p WriteErrorPolicy) {
+	errorPolicyMu.Lock()
+	errorPolicy = p
+	errorPolicyMu.Unlock()
+}
+
+// SetErrorHandler calls fn for every failed write, nil turns it off
+func SetErrorHandler(fn ErrorHandler) {
+	errorPolicyMu.Lock()
+	errorHandler = fn
+	errorPolicyMu.Unlock()
+}
+
+// writeFailed applies the policy to an entry s couldn't write
+// it returns nil if a retry got it through
+func writeFailed(s Sink, m LogData, err error) error {
+	errorPolicyMu.RLock()
+	p, fn := errorPolicy, errorHandler
+	errorPolicyMu.RUnlock()
+
+	wait := p.Backoff
+	for i := 0; i < p.Retries; i++ {
+		recordError(err)
+		recordRetry()
+		time.Sleep(wait)
+		wait *= 2
+		if err = s.Write(m); err == nil {
+			return nil
+		}
+	}
+	if p.Stderr {
+		if _, serr := os.Stderr.Write(format(m)); serr == nil {
+			recordFallback()
+		} else {
+			recordLost()
+		}
+	} else {
+		recordLost()
+	}
+	if fn != nil {
+		fn(err, m)
+	}
+	return err
+}
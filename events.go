@@ -0,0 +1,50 @@
+package snakeLoggerFile
+
+// the values of the "event" field, reports can rely on these not changing
+const (
+	EventGameStart = "game_start"
+	EventGameEnd   = "game_end"
+	EventDeath     = "death"
+	EventFoodEaten = "food_eaten"
+	EventDecision  = "decision"
+)
+
+// event logs a report entry with the standard event field
+func (s *SnakeLogger) event(name, msg string, fields map[string]interface{}) {
+	fields["event"] = name
+	s.logFields(ReportLevel, msg, fields)
+}
+
+// GameStart reports the start of game id
+func (s *SnakeLogger) GameStart(id string, turn int) {
+	c := s.ForTurn(turn)
+	c.event(EventGameStart, "game start", map[string]interface{}{"game_id": id, "turn": turn})
+}
+
+// GameEnd reports the end of game id, result is something like "win" or "loss"
+func (s *SnakeLogger) GameEnd(id string, turn int, result string) {
+	c := s.ForTurn(turn)
+	c.event(EventGameEnd, "game end", map[string]interface{}{"game_id": id, "turn": turn, "result": result})
+}
+
+// Death reports our snake dying and why
+func (s *SnakeLogger) Death(cause string) {
+	s.event(EventDeath, "death", map[string]interface{}{"cause": cause})
+}
+
+// FoodEaten reports eating, health is the health after eating
+func (s *SnakeLogger) FoodEaten(health int) {
+	s.event(EventFoodEaten, "food eaten", map[string]interface{}{"health": health})
+}
+
+// Decision reports the move picked on turn and the score of every move looked at
+// the fields are chosen and scores (move to score), for move quality dashboards
+// scores is copied, so it can be reused for the next turn
+func (s *SnakeLogger) Decision(turn int, chosen string, scores map[string]float64) {
+	cp := make(map[string]float64, len(scores))
+	for k, v := range scores {
+		cp[k] = v
+	}
+	c := s.ForTurn(turn)
+	c.event(EventDecision, "decision "+chosen, map[string]interface{}{"chosen": chosen, "scores": cp})
+}
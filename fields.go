@@ -0,0 +1,127 @@
+package snakeLoggerFile
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// WithField returns a child logger that adds key=value to every entry
+// the parent is not changed
+func (s *SnakeLogger) WithField(key string, value interface{}) *SnakeLogger {
+	return s.WithFields(map[string]interface{}{key: value})
+}
+
+// WithFields returns a child logger that adds fields to every entry
+// the map is copied, so changing it later doesn't affect the logger
+// keys already on the parent are overwritten in the child
+func (s *SnakeLogger) WithFields(fields map[string]interface{}) *SnakeLogger {
+	c := s.clone()
+	c.fields = mergeFields(s.fields, fields)
+	return c
+}
+
+// Debugw logs m with fields for this entry only, given as key, value pairs:
+// s.Infow("chose move", "move", "up", "health", 42)
+// keys that are not strings are printed with fmt.Sprint,
+// a key left without a value gets nil
+func (s *SnakeLogger) Debugw(m string, kv ...interface{}) {
+	s.logw(DebugLevel, m, kv)
+}
+
+// Infow is Debugw at info level
+func (s *SnakeLogger) Infow(m string, kv ...interface{}) {
+	s.logw(InfoLevel, m, kv)
+}
+
+// Warnw is Debugw at warn level
+func (s *SnakeLogger) Warnw(m string, kv ...interface{}) {
+	s.logw(WarnLevel, m, kv)
+}
+
+// Errorw is Debugw at error level
+func (s *SnakeLogger) Errorw(m string, kv ...interface{}) {
+	s.logw(ErrorLevel, m, kv)
+}
+
+// Reportw is Debugw at report level
+func (s *SnakeLogger) Reportw(m string, kv ...interface{}) {
+	s.logw(ReportLevel, m, kv)
+}
+
+func (s *SnakeLogger) logw(level SnakeLoggerLevel, m string, kv []interface{}) {
+	if !s.enabled(level) {
+		return
+	}
+	now := time.Now()
+	s.send(level, m, now, mergeFields(pairFields(kv), callFields(level)))
+}
+
+// pairFields turns key, value pairs into fields
+func pairFields(kv []interface{}) map[string]interface{} {
+	if len(kv) == 0 {
+		return nil
+	}
+	res := make(map[string]interface{}, (len(kv)+1)/2)
+	for i := 0; i < len(kv); i += 2 {
+		k, ok := kv[i].(string)
+		if !ok {
+			k = fmt.Sprint(kv[i])
+		}
+		var v interface{}
+		if i+1 < len(kv) {
+			v = kv[i+1]
+		}
+		res[k] = v
+	}
+	return res
+}
+
+// mergeFields makes a new map out of all the sets given, later ones win
+// it gives nil when there aren't any fields, so entries without fields stay cheap
+func mergeFields(sets ...map[string]interface{}) map[string]interface{} {
+	n := 0
+	for _, set := range sets {
+		n += len(set)
+	}
+	if n == 0 {
+		return nil
+	}
+	res := make(map[string]interface{}, n)
+	for _, set := range sets {
+		for k, v := range set {
+			res[k] = v
+		}
+	}
+	return res
+}
+
+// defaultMaxFields is generous, it is only there to catch accidents
+const defaultMaxFields = 64
+
+// FieldsTruncatedKey is set to true on an entry that had fields dropped
+const FieldsTruncatedKey = "_fields_truncated"
+
+var maxFields int32 = defaultMaxFields
+
+// SetMaxFields caps how many fields one entry can carry, 64 by default
+// fields past the cap (in key order) are dropped and _fields_truncated=true
+// is added, so a giant map attached by mistake can't bloat every line
+// 0 or less means no cap
+func SetMaxFields(n int) {
+	atomic.StoreInt32(&maxFields, int32(n))
+}
+
+// capFields applies SetMaxFields to fields, which it never changes
+func capFields(fields map[string]interface{}) map[string]interface{} {
+	max := int(atomic.LoadInt32(&maxFields))
+	if max <= 0 || len(fields) <= max {
+		return fields
+	}
+	res := make(map[string]interface{}, max+1)
+	for _, k := range sortedKeys(fields)[:max] {
+		res[k] = fields[k]
+	}
+	res[FieldsTruncatedKey] = true
+	return res
+}
@@ -0,0 +1,370 @@
+package snakeLoggerFile
+
+import (
+	"container/list"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// defaultFileSink is the per snake file output that is always set up
+var defaultFileSink *fileSink
+
+// fileSink writes each entry to a file named after the snake
+// entries without a snake name go to generic.log
+type fileSink struct {
+	mu      sync.Mutex
+	basedir string
+	// written is every file this sink has written to since startup
+	written map[string]struct{}
+	// readyGen is the fileSystemGen basedir has been created in,
+	// 0 when it has to be made again
+	readyGen uint64
+	// rotation is set with SetRotation
+	rotation RotationConfig
+	// segments maps a <snake>.log symlink to the file it points at
+	segments map[string]string
+	// fixedDir is used instead of the $HOME based directory when set
+	fixedDir string
+	// backup gets a copy of every entry written, see SetBackupDir
+	backup *backupSink
+	// discard formats entries but doesn't write them, see SetDiscard
+	discard bool
+	// open is the cached file handles, lru orders them by last write
+	open    map[string]*handle
+	lru     *list.List
+	maxOpen int
+	// flushLevel and above are flushed right after they are written
+	flushLevel SnakeLoggerLevel
+	// nameFn picks the file for an entry, nil means DefaultFilename
+	nameFn func(LogData) string
+	// routeField and layout are what nameFn came from, when it was
+	// SetRouteField or SetFileLayout, for Config
+	routeField string
+	layout     FileLayout
+	// seqs is the last sequence number given out for each file, nil when off
+	seqs map[string]uint64
+	// output replaces the files when set, see SetOutput
+	output *WriterSink
+	// outputFn replaces the files with a writer per snake, see SetOutputFactory
+	outputFn func(snakeName string) io.Writer
+	outputs  map[string]*WriterSink
+	// days is the date of the last entry written to each file, for RotationConfig.Daily
+	days map[string]string
+	// started is when each file was first written to, for RotationConfig.MaxAge
+	started map[string]time.Time
+	// sweepStop stops the compression sweeper, see SetRotation
+	sweepMu   sync.Mutex
+	sweepStop chan struct{}
+	// dirIndex namespaces the files under <dir>/<dirIndex>, -1 when off
+	dirIndex int
+}
+
+// newFileSink returns the sink for the usual log directory
+// the directory itself is picked and created on the first write
+func newFileSink() *fileSink {
+	return &fileSink{
+		written:  map[string]struct{}{},
+		segments: map[string]string{},
+		days:     map[string]string{},
+		started:  map[string]time.Time{},
+		dirIndex: -1,
+		open:     map[string]*handle{},
+		lru:      list.New(),
+		maxOpen:  defaultMaxOpenFiles,
+		// so errors are on disk even if the process dies right after
+		flushLevel: ErrorLevel,
+	}
+}
+
+// prepareDir makes sure the log directory is there on the current file system
+// find home directory, since I am running this on similar linux systems, this should be all we need
+func (fs *fileSink) prepareDir(fsys FileSystem, gen uint64) {
+	if fs.readyGen == gen {
+		return
+	}
+	// handles from the old file system can't be used any more
+	if err := fs.closeAll(); err != nil {
+		recordError(err)
+	}
+	fs.segments = map[string]string{}
+	fs.basedir = fs.fixedDir
+	if fs.basedir == "" {
+		fs.basedir = logDir(fsys)
+	}
+	// clean, so every name built from it matches the segment and skip sets
+	fs.basedir = path.Clean(fs.basedir)
+	if fs.dirIndex >= 0 {
+		fs.basedir = path.Join(fs.basedir, strconv.Itoa(fs.dirIndex))
+	}
+	err := fsys.MkdirAll(fs.basedir, 0755)
+	if err != nil {
+		if !errors.Is(err, os.ErrExist) {
+			recordError(err)
+		}
+	}
+	fs.readyGen = gen
+}
+
+// Write implements Sink
+func (fs *fileSink) Write(m LogData) error {
+	var filename string
+
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	if fs.discard {
+		return DiscardSink{}.Write(m)
+	}
+	if fs.output != nil {
+		fs.mirror(m)
+		return fs.output.Write(m)
+	}
+	if fs.outputFn != nil {
+		fs.mirror(m)
+		return fs.factoryOutput(m.SnakeName).Write(m)
+	}
+	fsys, gen := fileSystemAndGen()
+	fs.prepareDir(fsys, gen)
+	filename = fs.entryFilename(m)
+	if fs.seqs != nil {
+		fs.seqs[filename]++
+		m.Seq = fs.seqs[filename]
+	}
+	fs.mirror(m)
+	bp := getBuffer()
+	b := appendFormat((*bp)[:0], m)
+	defer putBuffer(bp, b)
+	target := filename
+	day := entryDay(m)
+	if fs.rotation.Symlink {
+		seg, err := fs.segmentFor(fsys, filename, len(b), day)
+		if err != nil {
+			return err
+		}
+		target = seg
+	} else if fs.needsRotation(fsys, filename, len(b), day) {
+		if err := fs.closeHandle(filename); err != nil {
+			recordError(err)
+		}
+		if err := fs.rotateFile(fsys, filename, time.Now()); err != nil {
+			// keep writing to the big file rather than lose the line
+			recordError(err)
+		}
+	}
+	h, err := fs.getHandle(fsys, target)
+	if err != nil {
+		return err
+	}
+
+	n, err := h.w.Write(b)
+	h.size += int64(n)
+	if err != nil {
+		if !m.Level.below(ErrorLevel) {
+			h.important = true
+		}
+		return err
+	}
+	fs.written[filename] = struct{}{}
+	if fs.rotation.Daily && day != "" {
+		fs.days[target] = day
+	}
+	if _, ok := fs.started[target]; !ok && fs.rotation.MaxAge > 0 {
+		fs.started[target] = time.Now()
+	}
+	if !m.Level.below(fs.flushLevel) {
+		// a failed flush leaves the entry in the buffer for the flusher
+		err = h.w.Flush()
+		h.important = err != nil
+	} else if !m.Level.below(ErrorLevel) {
+		h.important = true
+	}
+	return err
+}
+
+// mirror queues m for the backup dir, if there is one, fs.mu must be held
+func (fs *fileSink) mirror(m LogData) {
+	if fs.backup == nil {
+		return
+	}
+	if err := fs.backup.write(m, fs.nameFn); err != nil {
+		recordError(err)
+	}
+}
+
+// DefaultFilename is the usual layout, <snake name>.log or generic.log
+func DefaultFilename(l LogData) string {
+	if l.SnakeName == "" {
+		return "generic.log"
+	}
+	return l.SnakeName + ".log"
+}
+
+// SetFilenameBuilder changes which file each entry is written to
+// fn returns a path relative to the log directory and may include
+// directories, like 2024/05/01/snake.log, which are created as needed
+// when looking up a snake's file (Rotate, OpenLogReader) fn only gets the
+// SnakeName, so it should not depend on anything else but the time.
+// Rotation sweeps only look at the top of the log directory.
+// nil goes back to DefaultFilename
+func SetFilenameBuilder(fn func(LogData) string) {
+	defaultFileSink.setNames(fn, "", PerSnake)
+}
+
+// setNames changes the filename builder, with the route field or layout it came from
+func (fs *fileSink) setNames(fn func(LogData) string, routeField string, layout FileLayout) {
+	fs.mu.Lock()
+	fs.nameFn = fn
+	fs.routeField = routeField
+	fs.layout = layout
+	fs.mu.Unlock()
+}
+
+// SetSequenceNumbers numbers the entries in each log file 1, 2, 3...
+// the number is given out as the entry is written, so a gap means lines
+// were lost and numbers out of time order mean they were queued out of order
+// the count for a file carries on across rotation, turning it off and on
+// starts every file from 1 again
+func SetSequenceNumbers(on bool) {
+	defaultFileSink.mu.Lock()
+	defer defaultFileSink.mu.Unlock()
+	if !on {
+		defaultFileSink.seqs = nil
+	} else if defaultFileSink.seqs == nil {
+		defaultFileSink.seqs = map[string]uint64{}
+	}
+}
+
+// entryFilename is the file for m, fs.mu must be held and prepareDir called
+func (fs *fileSink) entryFilename(m LogData) string {
+	return path.Join(fs.basedir, fs.relName(m))
+}
+
+// relName is the file for m inside the log directory, fs.mu must be held
+func (fs *fileSink) relName(m LogData) string {
+	fn := fs.nameFn
+	if fn == nil {
+		fn = DefaultFilename
+	}
+	return fn(m)
+}
+
+// fileKey is relName for callers not holding fs.mu
+func (fs *fileSink) fileKey(m LogData) string {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	return fs.relName(m)
+}
+
+// filename is the file for a snake, fs.mu must be held and prepareDir called
+func (fs *fileSink) filename(snakeName string) string {
+	return fs.entryFilename(LogData{SnakeName: snakeName})
+}
+
+// pathFor is the file entries for snakeName are written to
+func (fs *fileSink) pathFor(snakeName string) string {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	fs.prepareDir(fileSystemAndGen())
+	return fs.filename(snakeName)
+}
+
+// files returns the sorted list of files written so far
+func (fs *fileSink) files() []string {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	res := make([]string, 0, len(fs.written))
+	for name := range fs.written {
+		res = append(res, name)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// ActiveFiles lists the full path of every log file written this session
+func ActiveFiles() []string {
+	return defaultFileSink.files()
+}
+
+// openLogFile opens filename for appending
+// if that fails it tries once more after creating filename's directory,
+// in case it is new or was removed out from under us
+func openLogFile(fsys FileSystem, filename string) (File, error) {
+	f, err := fsys.OpenFile(filename, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err == nil {
+		return f, nil
+	}
+	if mkErr := fsys.MkdirAll(path.Dir(filename), 0755); mkErr != nil {
+		return nil, err
+	}
+	return fsys.OpenFile(filename, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+}
+
+// fallbackDirEnv names a directory to use when $HOME can't be written to
+const fallbackDirEnv = "SNAKELOGGER_FALLBACK_DIR"
+
+// logDir is the directory all the log files go in
+// $HOME/battlesnakeLogs if that can be written to, otherwise
+// $SNAKELOGGER_FALLBACK_DIR or /tmp/battlesnakeLogs
+func logDir(fsys FileSystem) string {
+	dir := os.Getenv("HOME")
+	if dir == "" {
+		fmt.Fprintln(os.Stderr, "snakeLogger: cannot get home dir, falling back")
+	} else {
+		dir = dir + "/battlesnakeLogs"
+		err := checkDirWritable(fsys, dir)
+		if err == nil {
+			return dir
+		}
+		fmt.Fprintln(os.Stderr, "snakeLogger: cannot use home dir, falling back:", err)
+	}
+	if dir = os.Getenv(fallbackDirEnv); dir != "" {
+		return dir
+	}
+	return "/tmp/battlesnakeLogs"
+}
+
+// SetBaseDir puts the log files in dir instead of $HOME/battlesnakeLogs,
+// it is created if it isn't there. An empty dir goes back to the $HOME
+// then /tmp fallback. Files already open are closed, the next write
+// opens them in the new place
+func SetBaseDir(dir string) {
+	fs := defaultFileSink
+	fs.mu.Lock()
+	fs.fixedDir = dir
+	fs.readyGen = 0
+	fs.mu.Unlock()
+}
+
+// SetDirIndex namespaces this process's log files under <base dir>/<index>,
+// so several workers on one host sharing a base dir (and the generic log)
+// don't write into each other's files. A negative index turns it off,
+// which is the default
+func SetDirIndex(index int) {
+	fs := defaultFileSink
+	fs.mu.Lock()
+	fs.dirIndex = index
+	fs.readyGen = 0
+	fs.mu.Unlock()
+}
+
+// baseDir is the directory the sink writes to, made if needed
+func (fs *fileSink) baseDir() string {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	fs.prepareDir(fileSystemAndGen())
+	return fs.basedir
+}
+
+// dir is the directory the sink is writing to, empty before the first write
+func (fs *fileSink) dir() string {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	return fs.basedir
+}
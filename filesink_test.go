@@ -0,0 +1,142 @@
+package snakeLoggerFile
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUnopenableFileKeepsWriting(t *testing.T) {
+	fsys := useMemFS(t)
+	fsys.FailOpen(func(name string) error {
+		if strings.HasSuffix(name, "/locked.log") {
+			return os.ErrPermission
+		}
+		return nil
+	})
+	before := Health().WriteErrors
+
+	l := NewLogger("debug")
+	l.ToSnake("locked").Info("never lands")
+	l.ToSnake("open").Info("still written")
+	Sync()
+
+	h := Health()
+	if h.WriteErrors <= before {
+		t.Errorf("WriteErrors = %d, want more than %d", h.WriteErrors, before)
+	}
+	if !errors.Is(h.LastError, os.ErrPermission) {
+		t.Errorf("LastError = %v, want a permission error", h.LastError)
+	}
+	if lines := readLog(t, fsys, "open.log"); countContaining(lines, "still written") != 1 {
+		t.Errorf("open.log = %q, the writer stopped after the failed open", lines)
+	}
+
+	// the writer is still going once the file can be opened
+	fsys.FailOpen(nil)
+	l.ToSnake("locked").Info("lands now")
+	if lines := readLog(t, fsys, "locked.log"); countContaining(lines, "lands now") != 1 {
+		t.Errorf("locked.log = %q, want the entry made after the failure stopped", lines)
+	}
+}
+
+func TestLogDirFallback(t *testing.T) {
+	tests := []struct {
+		name     string
+		home     string
+		fallback string
+		want     string
+	}{
+		{"home writable", "/home/snake", "/srv/logs", "/home/snake/battlesnakeLogs"},
+		{"home unwritable", "/nonexistent", "/srv/logs", "/srv/logs"},
+		{"home unwritable no fallback", "/nonexistent", "", "/tmp/battlesnakeLogs"},
+		{"no home", "", "/srv/logs", "/srv/logs"},
+		{"no home no fallback", "", "", "/tmp/battlesnakeLogs"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setenv(t, "HOME", tt.home)
+			setenv(t, fallbackDirEnv, tt.fallback)
+			fsys := NewMemFS()
+			fsys.FailOpen(func(name string) error {
+				if strings.HasPrefix(name, "/nonexistent/") {
+					return os.ErrPermission
+				}
+				return nil
+			})
+			if got := logDir(fsys); got != tt.want {
+				t.Errorf("logDir = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBaseDirIsCleaned(t *testing.T) {
+	fsys := useMemFS(t)
+	SetBaseDir("/logs/")
+
+	NewLogger("debug").ToSnake("slash").Info("cleaned")
+	if lines := readLog(t, fsys, "slash.log"); countContaining(lines, "cleaned") != 1 {
+		t.Errorf("slash.log = %q", lines)
+	}
+	if files := fsys.Files(); len(files) != 1 || files[0] != "/logs/slash.log" {
+		t.Errorf("files = %q, want only /logs/slash.log", files)
+	}
+}
+
+// brokenDiskFS opens files that take no writes
+type brokenDiskFS struct {
+	*MemFS
+}
+
+func (b brokenDiskFS) OpenFile(name string, flag int, perm os.FileMode) (File, error) {
+	f, err := b.MemFS.OpenFile(name, flag, perm)
+	if err != nil {
+		return nil, err
+	}
+	return brokenFile{f}, nil
+}
+
+type brokenFile struct {
+	File
+}
+
+func (brokenFile) Write([]byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestFailedWriteIsNotRecorded(t *testing.T) {
+	SetFileSystem(brokenDiskFS{NewMemFS()})
+	defer SetFileSystem(nil)
+	fs := newFileSink()
+	fs.rotation.MaxAge = time.Hour
+
+	// too big for the buffer, so it goes straight to the file and fails
+	big := LogData{SnakeName: "broken", Level: InfoLevel, Msg: strings.Repeat("x", 8192)}
+	if err := fs.Write(big); err == nil {
+		t.Fatal("Write = nil, want the disk error")
+	}
+	if len(fs.written) != 0 || len(fs.started) != 0 {
+		t.Errorf("written = %v, started = %v, want nothing recorded", fs.written, fs.started)
+	}
+}
+
+func TestFailedFlushStaysImportant(t *testing.T) {
+	SetFileSystem(brokenDiskFS{NewMemFS()})
+	defer SetFileSystem(nil)
+	fs := newFileSink()
+
+	if err := fs.Write(LogData{SnakeName: "broken", Level: ErrorLevel, Msg: "lost?"}); err == nil {
+		t.Fatal("Write = nil, want the flush error")
+	}
+	if len(fs.open) != 1 {
+		t.Fatalf("%d files open, want 1", len(fs.open))
+	}
+	for name, h := range fs.open {
+		if !h.important {
+			t.Errorf("%s is not important after its flush failed", name)
+		}
+	}
+}
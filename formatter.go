@@ -0,0 +1,258 @@
+package snakeLoggerFile
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Formatter turns a log entry into the bytes that get written out.
+// Each call should return a complete line, including the trailing newline
+type Formatter interface {
+	Format(l LogData) []byte
+}
+
+// AppendFormatter is a Formatter that can render into a buffer it is given,
+// which lets the sinks reuse buffers instead of allocating a line each time
+type AppendFormatter interface {
+	Formatter
+	AppendFormat(dst []byte, l LogData) []byte
+}
+
+// TextFormatter renders the original human readable line:
+// "<timestamp> <id> (<turn>) <function> [<sev>] <msg>"
+// any fields are added after the message as key=value, sorted by key
+// (map values are printed sorted too), so the same entry always gives
+// the same line, and a sequence number goes in front as "#<seq> " when there is one
+type TextFormatter struct {
+	// EscapeNewlines writes newlines in the message and field values as \n
+	// (and carriage returns as \r) so every entry stays on one line
+	EscapeNewlines bool
+	// ShortSeverity writes the severity as one letter, [D] [I] [W] [E] [R]
+	ShortSeverity bool
+	// ShowInstance adds the instance field (name and logger index) as
+	// instance=<instance> after the message, so lines from two loggers
+	// with the same snake name can be told apart
+	ShowInstance bool
+	// Delimiter, when set, switches to a plain "ts|id|turn|func|sev|msg" line
+	// split by Delimiter instead of the spaces and brackets above.
+	// Fields go before the message with any Delimiter in them escaped by a
+	// backslash, the message is left as is since it is always last
+	Delimiter string
+}
+
+// Format implements Formatter
+func (t TextFormatter) Format(l LogData) []byte {
+	return t.AppendFormat(nil, l)
+}
+
+// AppendFormat implements AppendFormatter
+func (t TextFormatter) AppendFormat(dst []byte, l LogData) []byte {
+	if t.Delimiter != "" {
+		return t.appendDelimited(dst, l)
+	}
+	if l.Seq != 0 {
+		dst = append(dst, '#')
+		dst = strconv.AppendUint(dst, l.Seq, 10)
+		dst = append(dst, ' ')
+	}
+	dst = append(dst, l.Timestamp...)
+	dst = append(dst, ' ')
+	dst = append(dst, l.ID...)
+	dst = append(dst, " ("...)
+	dst = strconv.AppendInt(dst, int64(l.Turn), 10)
+	dst = append(dst, ") <"...)
+	dst = append(dst, l.Function...)
+	dst = append(dst, "> ["...)
+	if t.ShortSeverity {
+		dst = append(dst, shortSev(l.Sev)...)
+	} else {
+		dst = append(dst, l.Sev...)
+	}
+	dst = append(dst, "] "...)
+	dst = t.appendText(dst, l.Msg)
+	if t.ShowInstance && l.Instance != "" {
+		dst = append(dst, " instance="...)
+		dst = append(dst, l.Instance...)
+	}
+	for _, k := range sortedKeys(l.Fields) {
+		dst = append(dst, ' ')
+		dst = append(dst, k...)
+		dst = append(dst, '=')
+		dst = t.appendText(dst, fieldText(l.Fields[k]))
+	}
+	return append(dst, '\n')
+}
+
+// appendDelimited is AppendFormat when a Delimiter is set
+func (t TextFormatter) appendDelimited(dst []byte, l LogData) []byte {
+	d := t.Delimiter
+	if l.Seq != 0 {
+		dst = strconv.AppendUint(dst, l.Seq, 10)
+		dst = append(dst, d...)
+	}
+	dst = append(dst, l.Timestamp...)
+	dst = append(dst, d...)
+	dst = append(dst, l.ID...)
+	dst = append(dst, d...)
+	dst = strconv.AppendInt(dst, int64(l.Turn), 10)
+	dst = append(dst, d...)
+	dst = append(dst, l.Function...)
+	dst = append(dst, d...)
+	if t.ShortSeverity {
+		dst = append(dst, shortSev(l.Sev)...)
+	} else {
+		dst = append(dst, l.Sev...)
+	}
+	if t.ShowInstance && l.Instance != "" {
+		dst = append(dst, d...)
+		dst = append(dst, "instance="...)
+		dst = append(dst, strings.ReplaceAll(l.Instance, d, `\`+d)...)
+	}
+	for _, k := range sortedKeys(l.Fields) {
+		dst = append(dst, d...)
+		dst = append(dst, strings.ReplaceAll(k, d, `\`+d)...)
+		dst = append(dst, '=')
+		dst = t.appendText(dst, strings.ReplaceAll(fieldText(l.Fields[k]), d, `\`+d))
+	}
+	dst = append(dst, d...)
+	dst = t.appendText(dst, l.Msg)
+	return append(dst, '\n')
+}
+
+// appendText adds free form text, escaping it if asked to
+func (t TextFormatter) appendText(dst []byte, s string) []byte {
+	if !t.EscapeNewlines {
+		return append(dst, s...)
+	}
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\n':
+			dst = append(dst, '\\', 'n')
+		case '\r':
+			dst = append(dst, '\\', 'r')
+		default:
+			dst = append(dst, s[i])
+		}
+	}
+	return dst
+}
+
+// fieldText is how a field value looks in text output
+func fieldText(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case json.RawMessage:
+		return string(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// shortSev is the one letter form of a severity name, the first letter upper cased
+func shortSev(sev string) string {
+	if sev == "" {
+		return ""
+	}
+	return strings.ToUpper(sev[:1])
+}
+
+// sortedKeys gives the keys of fields in a stable order
+func sortedKeys(fields map[string]interface{}) []string {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+var (
+	formatterMu sync.RWMutex
+	formatter   Formatter = TextFormatter{}
+	// levelFormatters override formatter for one level, see SetLevelFormatter
+	levelFormatters [256]Formatter
+)
+
+// SetFormatter changes how the writer renders every log entry
+// passing nil puts back the default TextFormatter
+func SetFormatter(f Formatter) {
+	if f == nil {
+		f = TextFormatter{}
+	}
+	formatterMu.Lock()
+	formatter = f
+	formatterMu.Unlock()
+}
+
+// Format picks one of the built in formatters, see SetFormat
+type Format int
+
+const (
+	// FormatText is the human readable line, the default
+	FormatText Format = iota
+	// FormatJSON is one JSON object per line, see JSONFormatter
+	FormatJSON
+)
+
+// SetFormat switches every logger without its own formatter between
+// text and JSON lines, it is shorthand for SetFormatter
+func SetFormat(f Format) {
+	if f == FormatJSON {
+		SetFormatter(JSONFormatter{})
+		return
+	}
+	SetFormatter(TextFormatter{})
+}
+
+// SetLevelFormatter renders entries at level with f instead of the package
+// formatter, like JSON report lines in an otherwise text log:
+// SetLevelFormatter(ReportLevel, JSONFormatter{})
+// a logger's own formatter still wins, nil goes back to the package formatter
+func SetLevelFormatter(level SnakeLoggerLevel, f Formatter) {
+	formatterMu.Lock()
+	levelFormatters[level] = f
+	formatterMu.Unlock()
+}
+
+func currentFormatter() Formatter {
+	formatterMu.RLock()
+	defer formatterMu.RUnlock()
+	return formatter
+}
+
+// formatterFor is the formatter for entries at level without their own
+func formatterFor(level SnakeLoggerLevel) Formatter {
+	formatterMu.RLock()
+	defer formatterMu.RUnlock()
+	if f := levelFormatters[level]; f != nil {
+		return f
+	}
+	return formatter
+}
+
+// format renders l with the formatter of the logger that made it,
+// or the one for its level or the package formatter if that logger didn't pick one
+// entries from Raw come back untouched
+func format(l LogData) []byte {
+	return appendFormat(nil, l)
+}
+
+// appendFormat is format, but renders into dst when the formatter allows it
+func appendFormat(dst []byte, l LogData) []byte {
+	if l.raw != nil {
+		return append(dst, l.raw...)
+	}
+	f := l.formatter
+	if f == nil {
+		f = formatterFor(l.Level)
+	}
+	if af, ok := f.(AppendFormatter); ok {
+		return af.AppendFormat(dst, l)
+	}
+	return append(dst, f.Format(l)...)
+}
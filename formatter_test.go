@@ -0,0 +1,67 @@
+package snakeLoggerFile
+
+import (
+	"fmt"
+	"testing"
+)
+
+// fixedEntry is an entry with every header field set, for checking formatter output
+func fixedEntry(msg string, fields map[string]interface{}) LogData {
+	return LogData{Timestamp: "ts", ID: "id", Turn: 3, Function: "fn", Sev: "info", Msg: msg, Fields: fields}
+}
+
+func TestTextFormatterDelimiter(t *testing.T) {
+	tests := []struct {
+		name   string
+		f      TextFormatter
+		msg    string
+		fields map[string]interface{}
+		want   string
+	}{
+		{"default spaces", TextFormatter{}, "a|b", nil, "ts id (3) <fn> [info] a|b\n"},
+		{"pipe", TextFormatter{Delimiter: "|"}, "moving up", nil, "ts|id|3|fn|info|moving up\n"},
+		{"message holding the delimiter stays last", TextFormatter{Delimiter: "|"}, "a|b|c", nil, "ts|id|3|fn|info|a|b|c\n"},
+		{"fields escaped before the message", TextFormatter{Delimiter: "|"}, "m",
+			map[string]interface{}{"k|1": "v|2"}, `ts|id|3|fn|info|k\|1=v\|2|m` + "\n"},
+		{"multi byte delimiter", TextFormatter{Delimiter: " :: ", ShortSeverity: true}, "m", nil, "ts :: id :: 3 :: fn :: I :: m\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(tt.f.Format(fixedEntry(tt.msg, tt.fields))); got != tt.want {
+				t.Errorf("got  %q\nwant %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFieldOrderIsStable(t *testing.T) {
+	keys := []string{"zeta", "alpha", "mid", "beta", "omega", "gamma"}
+	head := fmt.Sprintf(`{"v":%d,"id":"id","sev":"info","msg":"m","timestamp":"ts","unix_timestamp":0,`, SchemaVersion)
+	tests := []struct {
+		name string
+		f    Formatter
+		want string
+	}{
+		{"text", TextFormatter{}, "ts id (3) <fn> [info] m alpha=1 beta=3 gamma=5 mid=2 omega=4 zeta=0\n"},
+		{"json", JSONFormatter{}, head +
+			`"turn":3,"function":"fn","snake_name":"","fields":{"alpha":1,"beta":3,"gamma":5,"mid":2,"omega":4,"zeta":0}}` + "\n"},
+		{"flattened json", JSONFormatter{FlattenFields: true}, head +
+			`"turn":3,"function":"fn","snake_name":"","alpha":1,"beta":3,"gamma":5,"mid":2,"omega":4,"zeta":0}` + "\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// the maps are built in a different order each run and
+			// iterate in a random one, the line must not change
+			for run := 0; run < 50; run++ {
+				fields := map[string]interface{}{}
+				for i := range keys {
+					j := (i + run) % len(keys)
+					fields[keys[j]] = j
+				}
+				if got := string(tt.f.Format(fixedEntry("m", fields))); got != tt.want {
+					t.Fatalf("run %d:\ngot  %s\nwant %s", run, got, tt.want)
+				}
+			}
+		})
+	}
+}
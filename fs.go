@@ -0,0 +1,114 @@
+package snakeLoggerFile
+
+import (
+	"io"
+	"os"
+	"sync"
+)
+
+// File is an open log file
+type File interface {
+	io.Writer
+	io.Closer
+}
+
+// FileSystem is every file operation the writer needs
+// the default goes straight to the os package, tests can swap in a MemFS
+// it doesn't have to be comparable
+type FileSystem interface {
+	OpenFile(name string, flag int, perm os.FileMode) (File, error)
+	Open(name string) (io.ReadCloser, error)
+	MkdirAll(path string, perm os.FileMode) error
+	Remove(name string) error
+	Rename(oldpath, newpath string) error
+	Symlink(oldname, newname string) error
+	Stat(name string) (os.FileInfo, error)
+	Lstat(name string) (os.FileInfo, error)
+	// ReadDir lists the files in dir, sorted by name
+	ReadDir(dir string) ([]os.FileInfo, error)
+}
+
+// osFS is the real disk
+type osFS struct{}
+
+func (osFS) OpenFile(name string, flag int, perm os.FileMode) (File, error) {
+	return os.OpenFile(name, flag, perm)
+}
+
+func (osFS) Open(name string) (io.ReadCloser, error) {
+	return os.Open(name)
+}
+
+func (osFS) MkdirAll(path string, perm os.FileMode) error {
+	return os.MkdirAll(path, perm)
+}
+
+func (osFS) Remove(name string) error {
+	return os.Remove(name)
+}
+
+func (osFS) Rename(oldpath, newpath string) error {
+	return os.Rename(oldpath, newpath)
+}
+
+func (osFS) Symlink(oldname, newname string) error {
+	return os.Symlink(oldname, newname)
+}
+
+func (osFS) Stat(name string) (os.FileInfo, error) {
+	return os.Stat(name)
+}
+
+func (osFS) Lstat(name string) (os.FileInfo, error) {
+	return os.Lstat(name)
+}
+
+func (osFS) ReadDir(dir string) ([]os.FileInfo, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]os.FileInfo, 0, len(entries))
+	for _, e := range entries {
+		info, err := e.Info()
+		if err != nil {
+			// removed since the listing, skip it
+			continue
+		}
+		res = append(res, info)
+	}
+	return res, nil
+}
+
+var (
+	fileSystemMu sync.RWMutex
+	fileSystem   FileSystem = osFS{}
+	// fileSystemGen goes up on every SetFileSystem, the sinks use it to see
+	// a change since FileSystem values may not be comparable
+	fileSystemGen uint64 = 1
+)
+
+// SetFileSystem changes where the log files are written
+// passing nil goes back to the real disk
+func SetFileSystem(f FileSystem) {
+	if f == nil {
+		f = osFS{}
+	}
+	fileSystemMu.Lock()
+	fileSystem = f
+	fileSystemGen++
+	fileSystemMu.Unlock()
+}
+
+func currentFileSystem() FileSystem {
+	fileSystemMu.RLock()
+	defer fileSystemMu.RUnlock()
+	return fileSystem
+}
+
+// fileSystemAndGen is currentFileSystem and the generation it was set in
+func fileSystemAndGen() (FileSystem, uint64) {
+	fileSystemMu.RLock()
+	defer fileSystemMu.RUnlock()
+	return fileSystem, fileSystemGen
+}
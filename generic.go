@@ -0,0 +1,90 @@
+package snakeLoggerFile
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	genericSourceMu sync.RWMutex
+	genericSource   string
+	// genericMin is a SnakeLoggerLevel, kept as uint32 so it can be changed atomically
+	genericMin uint32
+)
+
+// SetGenericLevel drops generic entries below level, on top of the
+// level of the logger that made them, so stray debug lines from loggers
+// without a snake name, or sent with the GENERIC prefix, stay out
+// of the generic log. The default, DebugLevel, drops nothing extra
+func SetGenericLevel(level SnakeLoggerLevel) {
+	atomic.StoreUint32(&genericMin, uint32(level))
+}
+
+func genericLevel() SnakeLoggerLevel {
+	return SnakeLoggerLevel(atomic.LoadUint32(&genericMin))
+}
+
+// SetGenericSource tags generic log entries with a "source" field,
+// so lines in generic.log can be traced back to a process
+// something like the hostname, or hostname plus a worker label
+// an empty tag (the default) adds nothing
+func SetGenericSource(tag string) {
+	genericSourceMu.Lock()
+	genericSource = tag
+	genericSourceMu.Unlock()
+}
+
+// tagGeneric adds the source field to m if one is set
+func tagGeneric(m LogData) LogData {
+	genericSourceMu.RLock()
+	tag := genericSource
+	genericSourceMu.RUnlock()
+	if tag == "" {
+		return m
+	}
+	m.Fields = mergeFields(m.Fields, map[string]interface{}{"source": tag})
+	return m
+}
+
+// genericLogger is s with the game and snake taken off, so entries go to generic.log
+func (s *SnakeLogger) genericLogger() *SnakeLogger {
+	c := s.clone()
+	c.id = ""
+	c.name = ""
+	return c
+}
+
+// Generic writes m at info level to generic.log, whichever snake s is for,
+// with no ID. It replaces starting the message with "GENERIC", which still
+// works but is deprecated: it only clears the ID and the entry stays in the snake's file
+func (s *SnakeLogger) Generic(m string) {
+	if !s.enabled(InfoLevel) {
+		return
+	}
+	now := time.Now()
+	s.genericLogger().send(InfoLevel, m, now, callFields(InfoLevel))
+}
+
+// Genericf is Generic with formatting
+func (s *SnakeLogger) Genericf(format string, v ...interface{}) {
+	if !s.enabled(InfoLevel) {
+		return
+	}
+	now := time.Now()
+	msg := fmt.Sprintf(format, v...)
+	s.genericLogger().send(InfoLevel, msg, now, callFields(InfoLevel))
+}
+
+// hasGenericPrefix is the deprecated GENERIC message prefix, which only
+// counts when a space or ':' follows it (or nothing does), so a message
+// like "GENERICALLY..." is left alone
+func hasGenericPrefix(msg string) bool {
+	if !strings.HasPrefix(msg, "GENERIC") {
+		return false
+	}
+	rest := msg[len("GENERIC"):]
+	return rest == "" || rest[0] == ' ' || rest[0] == ':'
+}
@@ -0,0 +1,280 @@
+package snakeLoggerFile
+
+import (
+	"bufio"
+	"container/list"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// defaultMaxOpenFiles is how many log files are kept open at once
+const defaultMaxOpenFiles = 64
+
+// handle is a log file kept open between writes
+type handle struct {
+	name string
+	f    File
+	w    *bufio.Writer
+	// size is the file size including what is still buffered
+	size int64
+	// important is set when an error or report entry is waiting in the buffer
+	important bool
+	elem      *list.Element
+	// last is when the handle was last written through
+	last time.Time
+}
+
+// SetFlushLevel makes entries at level or above get flushed to disk as soon
+// as they are written, lower ones wait in the buffer until the writer is idle
+// the default is ErrorLevel, NullLevel leaves everything to the idle flush
+func SetFlushLevel(level SnakeLoggerLevel) {
+	defaultFileSink.mu.Lock()
+	defaultFileSink.flushLevel = level
+	defaultFileSink.mu.Unlock()
+}
+
+// defaultFlushInterval is the longest buffered lines wait when the writer is never idle
+const defaultFlushInterval = time.Second
+
+// flushInterval is a time.Duration
+var flushInterval = int64(defaultFlushInterval)
+
+// SetFlushInterval sets the longest the writer goes without flushing
+// while it is busy, it always flushes when the queue empties
+// 0 or less leaves it to the idle flush and full buffers
+func SetFlushInterval(d time.Duration) {
+	atomic.StoreInt64(&flushInterval, int64(d))
+}
+
+// flushDue says if the flush interval has passed since last
+func flushDue(last time.Time) bool {
+	d := time.Duration(atomic.LoadInt64(&flushInterval))
+	return d > 0 && time.Since(last) >= d
+}
+
+// SetMaxOpenFiles caps how many log files are held open at once
+// when a new file is needed past the cap, the least recently written
+// one is flushed and closed, it is opened again on its next write
+func SetMaxOpenFiles(n int) {
+	if n < 1 {
+		n = 1
+	}
+	defaultFileSink.mu.Lock()
+	defaultFileSink.maxOpen = n
+	for len(defaultFileSink.open) > n {
+		defaultFileSink.evict()
+	}
+	defaultFileSink.mu.Unlock()
+}
+
+var (
+	idleMu      sync.Mutex
+	idleStop    chan struct{}
+	idleTimeout time.Duration
+)
+
+// SetIdleTimeout closes log files that haven't been written to for d,
+// so a snake that stopped playing doesn't hold its file open forever
+// they are opened again on their next write. 0 or less turns it off,
+// which is the default, files are then only closed past SetMaxOpenFiles
+func SetIdleTimeout(d time.Duration) {
+	idleMu.Lock()
+	defer idleMu.Unlock()
+	if idleStop != nil {
+		close(idleStop)
+		idleStop = nil
+	}
+	idleTimeout = 0
+	if d > 0 {
+		idleTimeout = d
+		idleStop = make(chan struct{})
+		go closeIdleLoop(defaultFileSink, d, idleStop)
+	}
+}
+
+// closeIdleLoop runs closeIdle a few times per timeout until stop is closed
+func closeIdleLoop(fs *fileSink, d time.Duration, stop chan struct{}) {
+	t := time.NewTicker(d / 2)
+	defer t.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case now := <-t.C:
+			fs.mu.Lock()
+			fs.closeIdle(now.Add(-d))
+			fs.mu.Unlock()
+		}
+	}
+}
+
+// closeIdle closes every handle last written before cutoff, fs.mu must be held
+// the lru list is in write order, so it stops at the first recent one
+func (fs *fileSink) closeIdle(cutoff time.Time) {
+	for e := fs.lru.Back(); e != nil; e = fs.lru.Back() {
+		h := e.Value.(*handle)
+		if !h.last.Before(cutoff) {
+			return
+		}
+		if err := fs.closeHandle(h.name); err != nil {
+			recordError(err)
+		}
+	}
+}
+
+// getHandle returns the open handle for name, opening it if needed
+// fs.mu must be held
+func (fs *fileSink) getHandle(fsys FileSystem, name string) (*handle, error) {
+	if h, ok := fs.open[name]; ok {
+		fs.lru.MoveToFront(h.elem)
+		h.last = time.Now()
+		return h, nil
+	}
+	for len(fs.open) >= fs.maxOpen && fs.lru.Len() > 0 {
+		fs.evict()
+	}
+	f, err := openLogFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	h := &handle{name: name, f: f, w: bufio.NewWriter(f), last: time.Now()}
+	if info, err := fsys.Stat(name); err == nil {
+		h.size = info.Size()
+	}
+	h.elem = fs.lru.PushFront(h)
+	fs.open[name] = h
+	return h, nil
+}
+
+// evict closes the least recently used handle, fs.mu must be held
+func (fs *fileSink) evict() {
+	oldest := fs.lru.Back()
+	if oldest == nil {
+		return
+	}
+	if err := fs.closeHandle(oldest.Value.(*handle).name); err != nil {
+		recordError(err)
+	}
+}
+
+// closeHandle flushes and closes name if it is open, fs.mu must be held
+func (fs *fileSink) closeHandle(name string) error {
+	h, ok := fs.open[name]
+	if !ok {
+		return nil
+	}
+	delete(fs.open, name)
+	fs.lru.Remove(h.elem)
+	err := h.w.Flush()
+	if cerr := h.f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// closeAll flushes and closes every open handle, fs.mu must be held
+func (fs *fileSink) closeAll() error {
+	var first error
+	for name := range fs.open {
+		if err := fs.closeHandle(name); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// Flush writes out everything buffered for every open file,
+// or for the output given to SetOutput
+func (fs *fileSink) Flush() error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	if fs.output != nil {
+		return fs.output.flush()
+	}
+	var first error
+	for _, ws := range fs.outputs {
+		if err := ws.flush(); err != nil && first == nil {
+			first = err
+		}
+	}
+	for _, h := range fs.open {
+		if err := h.w.Flush(); err != nil && first == nil {
+			first = err
+		}
+		h.important = false
+	}
+	return first
+}
+
+// flushImportant flushes only the files with error or report entries waiting
+func (fs *fileSink) flushImportant() error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	var first error
+	for _, h := range fs.open {
+		if !h.important {
+			continue
+		}
+		if err := h.w.Flush(); err != nil && first == nil {
+			first = err
+		}
+		h.important = false
+	}
+	return first
+}
+
+// flushFile flushes the buffer for one file, the segment behind it
+// when symlink rotation is on
+func (fs *fileSink) flushFile(filename string) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	if seg, ok := fs.segments[filename]; ok {
+		filename = seg
+	}
+	h, ok := fs.open[filename]
+	if !ok {
+		return nil
+	}
+	h.important = false
+	return h.w.Flush()
+}
+
+// FlushFor makes sure everything logged for snakeName so far is in its
+// file, "" is the generic log. Entries still being handed to the writer
+// by their log call may be missed
+func FlushFor(snakeName string) error {
+	name := defaultFileSink.pathFor(snakeName)
+	key := defaultFileSink.fileKey(LogData{SnakeName: snakeName})
+	var err error
+	runOnWriter(func() {
+		flushRepeats(key)
+		err = defaultFileSink.flushFile(name)
+	})
+	return err
+}
+
+// FlushImportant makes sure every error and report entry logged so far is
+// on disk, before doing something that might bring the process down
+// entries still being handed to the writer by their log call may be missed
+// only files holding such entries are flushed, so it is cheaper than a full
+// flush, though debug and info lines before them in the same file go out too
+func FlushImportant() error {
+	var err error
+	runOnWriter(func() {
+		err = defaultFileSink.flushImportant()
+	})
+	return err
+}
+
+// fileSize is what name will be once its buffer is written
+func (fs *fileSink) fileSize(fsys FileSystem, name string) (int64, bool) {
+	if h, ok := fs.open[name]; ok {
+		return h.size, true
+	}
+	info, err := fsys.Stat(name)
+	if err != nil {
+		return 0, false
+	}
+	return info.Size(), true
+}
@@ -0,0 +1,39 @@
+package snakeLoggerFile
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestMaxOpenFilesCyclesHandles(t *testing.T) {
+	fsys := NewMemFS()
+	SetFileSystem(fsys)
+	defer SetFileSystem(nil)
+	fs := newFileSink()
+	fs.maxOpen = 4
+
+	for round := 0; round < 2; round++ {
+		for i := 0; i < 20; i++ {
+			m := LogData{SnakeName: fmt.Sprintf("snake%d", i), Msg: fmt.Sprintf("round %d", round)}
+			if err := fs.Write(m); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := fs.Flush(); err != nil {
+			t.Fatal(err)
+		}
+		if n := fsys.OpenCount(); n > 4 {
+			t.Fatalf("round %d: %d files open, want at most 4", round, n)
+		}
+	}
+	for i := 0; i < 20; i++ {
+		b, err := fsys.ReadFile(fmt.Sprintf("%s/snake%d.log", fs.dir(), i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Count(string(b), "round 0") != 1 || strings.Count(string(b), "round 1") != 1 {
+			t.Errorf("snake%d.log = %q, want a line from each round", i, b)
+		}
+	}
+}
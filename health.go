@@ -0,0 +1,71 @@
+package snakeLoggerFile
+
+import (
+	"sync"
+	"time"
+)
+
+// HealthStatus is a snapshot of problems the writer has run into
+type HealthStatus struct {
+	// WriteErrors counts every failed open, write or close
+	WriteErrors uint64
+	// LastError is the most recent failure, nil if there has not been one
+	LastError error
+	// LastErrorAt is when LastError happened
+	LastErrorAt time.Time
+	// Skipped counts entries a sink didn't get because its circuit breaker
+	// was open, see SetCircuitBreaker
+	Skipped uint64
+	// Retries counts writes tried again by the WriteErrorPolicy
+	Retries uint64
+	// Fallbacks counts entries written to stderr because a sink failed
+	Fallbacks uint64
+	// Lost counts entries a sink failed to write that no retry or stderr saved
+	Lost uint64
+}
+
+var (
+	healthMu sync.Mutex
+	health   HealthStatus
+)
+
+// Health returns the current state of the writer
+func Health() HealthStatus {
+	healthMu.Lock()
+	defer healthMu.Unlock()
+	return health
+}
+
+// recordError keeps track of a writer failure instead of killing the process
+func recordError(err error) {
+	healthMu.Lock()
+	health.WriteErrors++
+	health.LastError = err
+	health.LastErrorAt = time.Now()
+	healthMu.Unlock()
+}
+
+// recordSkip counts an entry held back by a circuit breaker
+func recordSkip() {
+	healthMu.Lock()
+	health.Skipped++
+	healthMu.Unlock()
+}
+
+func recordRetry() {
+	healthMu.Lock()
+	health.Retries++
+	healthMu.Unlock()
+}
+
+func recordFallback() {
+	healthMu.Lock()
+	health.Fallbacks++
+	healthMu.Unlock()
+}
+
+func recordLost() {
+	healthMu.Lock()
+	health.Lost++
+	healthMu.Unlock()
+}
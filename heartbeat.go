@@ -0,0 +1,58 @@
+package snakeLoggerFile
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// entriesSeen counts entries the writer has handled since the last heartbeat
+var entriesSeen uint64
+
+var (
+	heartbeatMu   sync.Mutex
+	heartbeatStop chan struct{}
+	// heartbeatEvery is the current interval, 0 when off
+	heartbeatEvery time.Duration
+	// internalLogger is how the package logs about itself, to the generic log
+	internalLogger = &SnakeLogger{level: uint32(InfoLevel)}
+)
+
+// SetHeartbeat logs an info line to the generic log every interval
+// so a reader of the file can tell the writer is still alive
+// the count in the line includes the previous heartbeat
+// an interval of 0 or less turns it off
+func SetHeartbeat(interval time.Duration) {
+	heartbeatMu.Lock()
+	defer heartbeatMu.Unlock()
+	if heartbeatStop != nil {
+		close(heartbeatStop)
+		heartbeatStop = nil
+	}
+	heartbeatEvery = 0
+	if interval <= 0 {
+		return
+	}
+	heartbeatEvery = interval
+	heartbeatStop = make(chan struct{})
+	go heartbeat(interval, heartbeatStop)
+}
+
+func heartbeat(interval time.Duration, stop chan struct{}) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-t.C:
+			n := atomic.SwapUint64(&entriesSeen, 0)
+			msg := fmt.Sprintf("logger heartbeat, %d entries since last", n)
+			if h := Health(); h.LastError != nil {
+				msg += fmt.Sprintf(", last error %s ago: %v", time.Since(h.LastErrorAt).Round(time.Second), h.LastError)
+			}
+			internalLogger.Info(msg)
+		}
+	}
+}
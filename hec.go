@@ -0,0 +1,398 @@
+package snakeLoggerFile
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// SplunkConfig says where a SplunkSink sends events
+// only URL and Token are needed, the rest have defaults
+type SplunkConfig struct {
+	// URL is the HEC endpoint, https://splunk:8088 gets /services/collector/event added
+	URL   string
+	Token string
+	// Index, Source, SourceType and Host are set on every event when not empty
+	Index      string
+	Source     string
+	SourceType string
+	Host       string
+	// BatchSize is how many events go in one request, default 100
+	BatchSize int
+	// FlushInterval sends a part filled batch after this long, default 2s
+	FlushInterval time.Duration
+	// Timeout bounds each request, default 5s
+	Timeout time.Duration
+	// Retry is how a failed batch is retried, zero values come from DefaultRetryPolicy
+	// its QueueSize is how many events can wait to be batched
+	Retry RetryPolicy
+	// SpoolDir is where batches that still fail are kept until HEC is back,
+	// in hec-spool.json, default the log directory
+	SpoolDir string
+}
+
+// the environment variables read by SplunkConfigFromEnv
+const (
+	splunkURLEnv        = "SPLUNK_HEC_URL"
+	splunkTokenEnv      = "SPLUNK_HEC_TOKEN"
+	splunkIndexEnv      = "SPLUNK_HEC_INDEX"
+	splunkSourceEnv     = "SPLUNK_HEC_SOURCE"
+	splunkSourceTypeEnv = "SPLUNK_HEC_SOURCETYPE"
+	splunkHostEnv       = "SPLUNK_HEC_HOST"
+	splunkSpoolDirEnv   = "SPLUNK_HEC_SPOOL_DIR"
+)
+
+const (
+	defaultHECBatchSize     = 100
+	defaultHECFlushInterval = 2 * time.Second
+	defaultHECTimeout       = 5 * time.Second
+	hecEventPath            = "/services/collector/event"
+	hecSpoolFile            = "hec-spool.json"
+)
+
+// SplunkConfigFromEnv reads a SplunkConfig from SPLUNK_HEC_URL, SPLUNK_HEC_TOKEN,
+// SPLUNK_HEC_INDEX, SPLUNK_HEC_SOURCE, SPLUNK_HEC_SOURCETYPE, SPLUNK_HEC_HOST
+// and SPLUNK_HEC_SPOOL_DIR, anything not set is left at its default
+func SplunkConfigFromEnv() SplunkConfig {
+	return SplunkConfig{
+		URL:        os.Getenv(splunkURLEnv),
+		Token:      os.Getenv(splunkTokenEnv),
+		Index:      os.Getenv(splunkIndexEnv),
+		Source:     os.Getenv(splunkSourceEnv),
+		SourceType: os.Getenv(splunkSourceTypeEnv),
+		Host:       os.Getenv(splunkHostEnv),
+		SpoolDir:   os.Getenv(splunkSpoolDirEnv),
+	}
+}
+
+// SplunkSink ships entries to a Splunk HTTP Event Collector
+// entries are batched on its own goroutine, so the writer never waits on
+// the network. A batch that fails is retried with backoff, then spooled to
+// disk and sent again after the next batch that gets through
+type SplunkSink struct {
+	// counters first so they stay 64 bit aligned for atomic
+	sent    uint64
+	spooled uint64
+
+	cfg    SplunkConfig
+	url    string
+	client *http.Client
+	queue  chan LogData
+	done   chan struct{}
+	// mu guards closed, Write holds it for reading while it queues
+	// so Close can't close the queue under it
+	mu     sync.RWMutex
+	closed bool
+	// spoolMu is held while the spool file is written or replayed
+	spoolMu sync.Mutex
+}
+
+// NewSplunkSink starts a sink for cfg, add it with AddSink
+// it is an error if cfg has no URL or Token
+func NewSplunkSink(cfg SplunkConfig) (*SplunkSink, error) {
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("splunk sink needs a URL")
+	}
+	if cfg.Token == "" {
+		return nil, fmt.Errorf("splunk sink needs a token")
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = defaultHECBatchSize
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = defaultHECFlushInterval
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultHECTimeout
+	}
+	if cfg.Retry.MaxAttempts <= 0 {
+		cfg.Retry.MaxAttempts = DefaultRetryPolicy.MaxAttempts
+	}
+	if cfg.Retry.InitialBackoff <= 0 {
+		cfg.Retry.InitialBackoff = DefaultRetryPolicy.InitialBackoff
+	}
+	if cfg.Retry.MaxBackoff <= 0 {
+		cfg.Retry.MaxBackoff = DefaultRetryPolicy.MaxBackoff
+	}
+	if cfg.Retry.QueueSize <= 0 {
+		cfg.Retry.QueueSize = DefaultRetryPolicy.QueueSize
+	}
+	url := strings.TrimRight(cfg.URL, "/")
+	if !strings.Contains(strings.TrimPrefix(strings.TrimPrefix(url, "https://"), "http://"), "/") {
+		url += hecEventPath
+	}
+	s := &SplunkSink{
+		cfg:    cfg,
+		url:    url,
+		client: &http.Client{Timeout: cfg.Timeout},
+		queue:  make(chan LogData, cfg.Retry.QueueSize),
+		done:   make(chan struct{}),
+	}
+	go s.run()
+	return s, nil
+}
+
+// Write implements Sink, it only queues the entry
+// when the queue is full or the sink is closed the entry goes straight to the spool
+func (s *SplunkSink) Write(l LogData) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return s.spool([][]byte{s.event(l)})
+	}
+	select {
+	case s.queue <- l:
+		return nil
+	default:
+		return s.spool([][]byte{s.event(l)})
+	}
+}
+
+// Sent is how many events HEC has accepted
+func (s *SplunkSink) Sent() uint64 {
+	return atomic.LoadUint64(&s.sent)
+}
+
+// Spooled is how many events have been written to the spool file
+func (s *SplunkSink) Spooled() uint64 {
+	return atomic.LoadUint64(&s.spooled)
+}
+
+// Close stops taking entries and sends what is queued
+func (s *SplunkSink) Close() error {
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.queue)
+	}
+	s.mu.Unlock()
+	<-s.done
+	return nil
+}
+
+func (s *SplunkSink) run() {
+	defer close(s.done)
+	t := time.NewTicker(s.cfg.FlushInterval)
+	defer t.Stop()
+	var batch [][]byte
+	for {
+		select {
+		case l, ok := <-s.queue:
+			if !ok {
+				s.send(batch)
+				return
+			}
+			batch = append(batch, s.event(l))
+			if len(batch) >= s.cfg.BatchSize {
+				s.send(batch)
+				batch = nil
+			}
+		case <-t.C:
+			s.send(batch)
+			batch = nil
+		}
+	}
+}
+
+// hecEvent is one event in the HEC JSON format
+type hecEvent struct {
+	Time       float64         `json:"time"`
+	Host       string          `json:"host,omitempty"`
+	Source     string          `json:"source,omitempty"`
+	SourceType string          `json:"sourcetype,omitempty"`
+	Index      string          `json:"index,omitempty"`
+	Event      json.RawMessage `json:"event"`
+}
+
+// event encodes l, the entry itself is the JSON line JSONFormatter writes
+func (s *SplunkSink) event(l LogData) []byte {
+	var body []byte
+	if l.raw != nil {
+		body, _ = json.Marshal(string(l.raw))
+	} else {
+		body = bytes.TrimRight(JSONFormatter{}.Format(l), "\n")
+	}
+	b, err := json.Marshal(hecEvent{
+		Time:       hecTime(l.UnixTimeStamp),
+		Host:       s.cfg.Host,
+		Source:     s.cfg.Source,
+		SourceType: s.cfg.SourceType,
+		Index:      s.cfg.Index,
+		Event:      body,
+	})
+	if err != nil {
+		// the formatter falls back to text for values it can't encode
+		b, _ = json.Marshal(hecEvent{Time: hecTime(l.UnixTimeStamp), Event: mustString(l.String())})
+	}
+	return b
+}
+
+// mustString is s as a JSON string
+func mustString(s string) json.RawMessage {
+	b, _ := json.Marshal(s)
+	return b
+}
+
+// hecTime is a unix timestamp as the seconds HEC wants, see SetUnixPrecision
+func hecTime(ts int64) float64 {
+	switch UnixPrecision(atomic.LoadInt32(&unixPrecision)) {
+	case UnixMicros:
+		return float64(ts) / 1e6
+	case UnixMillis:
+		return float64(ts) / 1e3
+	case UnixSeconds:
+		return float64(ts)
+	default:
+		return float64(ts) / 1e9
+	}
+}
+
+// send delivers batch, retrying with backoff and spooling it if that fails
+// once a batch gets through whatever was spooled is sent too
+func (s *SplunkSink) send(batch [][]byte) {
+	if len(batch) == 0 {
+		return
+	}
+	body := bytes.Join(batch, []byte("\n"))
+	wait := s.cfg.Retry.InitialBackoff
+	var err error
+	for attempt := 1; attempt <= s.cfg.Retry.MaxAttempts; attempt++ {
+		if attempt > 1 {
+			time.Sleep(wait)
+			wait *= 2
+			if wait > s.cfg.Retry.MaxBackoff {
+				wait = s.cfg.Retry.MaxBackoff
+			}
+		}
+		if err = s.post(body); err == nil {
+			atomic.AddUint64(&s.sent, uint64(len(batch)))
+			s.replay()
+			return
+		}
+	}
+	recordError(fmt.Errorf("splunk HEC failed, spooling %d events: %w", len(batch), err))
+	if err := s.spool(batch); err != nil {
+		recordError(err)
+	}
+}
+
+// post makes one request to HEC
+func (s *SplunkSink) post(body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Splunk "+s.cfg.Token)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("splunk HEC returned %s: %s", resp.Status, bytes.TrimSpace(msg))
+	}
+	io.Copy(ioutil.Discard, resp.Body)
+	return nil
+}
+
+// spoolPath is the file failed events are kept in
+func (s *SplunkSink) spoolPath() string {
+	dir := s.cfg.SpoolDir
+	if dir == "" {
+		dir = defaultFileSink.baseDir()
+	}
+	return path.Join(dir, hecSpoolFile)
+}
+
+// spool adds events to the spool file, one per line
+func (s *SplunkSink) spool(events [][]byte) error {
+	s.spoolMu.Lock()
+	defer s.spoolMu.Unlock()
+	fsys := currentFileSystem()
+	name := s.spoolPath()
+	if err := fsys.MkdirAll(path.Dir(name), 0755); err != nil {
+		return err
+	}
+	f, err := fsys.OpenFile(name, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	for _, e := range events {
+		if _, err = f.Write(append(e, '\n')); err != nil {
+			break
+		}
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		atomic.AddUint64(&s.spooled, uint64(len(events)))
+	}
+	return err
+}
+
+// replay sends the spool file in batches and removes it once it is all through
+// a batch that fails leaves the file for next time
+func (s *SplunkSink) replay() {
+	s.spoolMu.Lock()
+	defer s.spoolMu.Unlock()
+	fsys := currentFileSystem()
+	name := s.spoolPath()
+	f, err := fsys.Open(name)
+	if err != nil {
+		return
+	}
+	b, err := ioutil.ReadAll(f)
+	f.Close()
+	if err != nil {
+		recordError(err)
+		return
+	}
+	lines := bytes.Split(bytes.TrimRight(b, "\n"), []byte("\n"))
+	for len(lines) > 0 && len(lines[0]) > 0 {
+		n := s.cfg.BatchSize
+		if n > len(lines) {
+			n = len(lines)
+		}
+		if err := s.post(bytes.Join(lines[:n], []byte("\n"))); err != nil {
+			s.rewriteSpool(fsys, name, lines)
+			return
+		}
+		atomic.AddUint64(&s.sent, uint64(n))
+		lines = lines[n:]
+	}
+	if err := fsys.Remove(name); err != nil {
+		recordError(err)
+	}
+}
+
+// rewriteSpool leaves only the lines still to send in the spool file
+func (s *SplunkSink) rewriteSpool(fsys FileSystem, name string, lines [][]byte) {
+	f, err := fsys.OpenFile(name, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		recordError(err)
+		return
+	}
+	for _, l := range lines {
+		if _, err = f.Write(append(l, '\n')); err != nil {
+			break
+		}
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		recordError(err)
+	}
+}
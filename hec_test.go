@@ -0,0 +1,27 @@
+package snakeLoggerFile
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSplunkSinkWriteAfterClose(t *testing.T) {
+	fsys := useMemFS(t)
+	s, err := NewSplunkSink(SplunkConfig{URL: "http://127.0.0.1:1", Token: "t", SpoolDir: "/spool"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+	if err := s.Write(LogData{SnakeName: "late", Msg: "after close"}); err != nil {
+		t.Fatalf("Write after Close: %v", err)
+	}
+	s.Close()
+
+	if got := s.Spooled(); got != 1 {
+		t.Errorf("Spooled() = %d, want 1", got)
+	}
+	b, err := fsys.ReadFile("/spool/" + hecSpoolFile)
+	if err != nil || !strings.Contains(string(b), "after close") {
+		t.Errorf("spool = %q, %v: want the entry kept for later", b, err)
+	}
+}
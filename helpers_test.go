@@ -0,0 +1,95 @@
+package snakeLoggerFile
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+// TestMain keeps every test off the real disk, tests that look at the
+// files put in their own MemFS with useMemFS
+func TestMain(m *testing.M) {
+	SetFileSystem(NewMemFS())
+	os.Exit(m.Run())
+}
+
+// useMemFS writes the log files into a fresh MemFS under /logs
+// until the test ends
+func useMemFS(t testing.TB) *MemFS {
+	t.Helper()
+	fsys := NewMemFS()
+	SetFileSystem(fsys)
+	SetBaseDir("/logs")
+	t.Cleanup(func() {
+		Sync()
+		SetFileSystem(NewMemFS())
+		SetBaseDir("")
+	})
+	return fsys
+}
+
+// readLog waits for the writer and returns the lines of /logs/<name>
+func readLog(t *testing.T, fsys *MemFS, name string) []string {
+	t.Helper()
+	if err := Sync(); err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	b, err := fsys.ReadFile("/logs/" + name)
+	if err != nil {
+		t.Fatalf("reading %s: %v", name, err)
+	}
+	return strings.Split(strings.TrimSuffix(string(b), "\n"), "\n")
+}
+
+// countContaining is how many of lines contain s
+func countContaining(lines []string, s string) int {
+	n := 0
+	for _, l := range lines {
+		if strings.Contains(l, s) {
+			n++
+		}
+	}
+	return n
+}
+
+// setenv sets key for the rest of the test, empty unsets it
+// (t.Setenv is newer than the go version in go.mod)
+func setenv(t *testing.T, key, value string) {
+	t.Helper()
+	old, had := os.LookupEnv(key)
+	if value == "" {
+		os.Unsetenv(key)
+	} else {
+		os.Setenv(key, value)
+	}
+	t.Cleanup(func() {
+		if had {
+			os.Setenv(key, old)
+		} else {
+			os.Unsetenv(key)
+		}
+	})
+}
+
+// onlySink sends everything to s until the test ends
+func onlySink(t *testing.T, s Sink) {
+	t.Helper()
+	sinksMu.Lock()
+	old := sinks
+	sinks = []sinkEntry{{sink: s, level: DebugLevel, brk: &breaker{}}}
+	sinksMu.Unlock()
+	t.Cleanup(func() {
+		sinksMu.Lock()
+		sinks = old
+		sinksMu.Unlock()
+	})
+}
+
+// runWriter puts entries through the package writer
+// and returns once it has finished with all of them
+func runWriter(entries ...LogData) {
+	for _, m := range entries {
+		writeChan <- m
+	}
+	Sync()
+}
@@ -0,0 +1,20 @@
+package snakeLoggerFile
+
+import (
+	"fmt"
+	"time"
+)
+
+// HTTP logs one served request as an info access log entry,
+// with method, path, status and duration_ms fields
+func (s *SnakeLogger) HTTP(method, path string, status int, dur time.Duration) {
+	if !s.enabled(InfoLevel) {
+		return
+	}
+	s.logFields(InfoLevel, fmt.Sprintf("%s %s %d %s", method, path, status, dur), map[string]interface{}{
+		"method":      method,
+		"path":        path,
+		"status":      status,
+		"duration_ms": dur.Milliseconds(),
+	})
+}
@@ -0,0 +1,68 @@
+package snakeLoggerFile
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// InstanceFormat builds the instance field from a snake name and logger index
+type InstanceFormat func(name string, index uint64) string
+
+// DefaultInstanceFormat gives "mysnake#3"
+func DefaultInstanceFormat(name string, index uint64) string {
+	return fmt.Sprintf("%s#%d", name, index)
+}
+
+var (
+	instanceFormatMu sync.RWMutex
+	instanceFormat   InstanceFormat = DefaultInstanceFormat
+)
+
+// SetInstanceFormat changes how the instance field is put together
+// nil goes back to DefaultInstanceFormat
+func SetInstanceFormat(f InstanceFormat) {
+	if f == nil {
+		f = DefaultInstanceFormat
+	}
+	instanceFormatMu.Lock()
+	instanceFormat = f
+	instanceFormatMu.Unlock()
+}
+
+// instanceName is the instance field for a logger, empty for generic logs
+func instanceName(name string, index uint64) string {
+	if name == "" {
+		return ""
+	}
+	instanceFormatMu.RLock()
+	f := instanceFormat
+	instanceFormatMu.RUnlock()
+	return f(name, index)
+}
+
+var (
+	autoIndex int32
+	// lastIndex is the last index handed out by SetAutoIndex
+	lastIndex uint64
+)
+
+// SetAutoIndex gives every logger made by NewLogger its own index,
+// counting up from 1, so two loggers accidentally sharing a snake name
+// can be told apart by their instance field (see TextFormatter.ShowInstance)
+// children keep their parent's index, UpdateIndex still overrides it
+func SetAutoIndex(on bool) {
+	var v int32
+	if on {
+		v = 1
+	}
+	atomic.StoreInt32(&autoIndex, v)
+}
+
+// nextIndex is the index for a new logger, 0 unless SetAutoIndex is on
+func nextIndex() uint64 {
+	if atomic.LoadInt32(&autoIndex) == 0 {
+		return 0
+	}
+	return atomic.AddUint64(&lastIndex, 1)
+}
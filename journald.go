@@ -0,0 +1,111 @@
+package snakeLoggerFile
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// JournaldSocket is where systemd-journald listens for native protocol messages
+const JournaldSocket = "/run/systemd/journal/socket"
+
+// journaldPriority maps the built in levels to syslog priorities
+var journaldPriority = map[SnakeLoggerLevel]int{
+	DebugLevel:  7,
+	InfoLevel:   6,
+	WarnLevel:   4,
+	ErrorLevel:  3,
+	ReportLevel: 5,
+}
+
+// JournaldSink sends entries to journald with the native protocol,
+// one datagram per entry with MESSAGE (just the message, journald keeps
+// its own timestamp), PRIORITY, SNAKE_NAME and TURN set,
+// plus GAME_ID and CODE_FUNC when there is an id or function
+type JournaldSink struct {
+	mu   sync.Mutex
+	path string
+	conn net.Conn
+}
+
+// NewJournaldSink returns a sink for the journald socket at path,
+// normally JournaldSocket. It connects on the first write
+func NewJournaldSink(path string) *JournaldSink {
+	return &JournaldSink{path: path}
+}
+
+// UseJournald sends everything to journald instead of the log files
+// when the journald socket is there, and says whether it did
+// when it isn't (not running under systemd) the files are left as they are
+func UseJournald() bool {
+	if _, err := os.Stat(JournaldSocket); err != nil {
+		return false
+	}
+	AddSink(NewJournaldSink(JournaldSocket), DebugLevel)
+	SetFileLevel(NullLevel)
+	return true
+}
+
+// Write implements Sink
+func (j *JournaldSink) Write(l LogData) error {
+	var b bytes.Buffer
+	journaldField(&b, "MESSAGE", l.Msg)
+	journaldField(&b, "PRIORITY", strconv.Itoa(priorityFor(l.Level)))
+	journaldField(&b, "SNAKE_NAME", l.SnakeName)
+	journaldField(&b, "TURN", strconv.Itoa(l.Turn))
+	if l.ID != "" {
+		journaldField(&b, "GAME_ID", l.ID)
+	}
+	if l.Function != "" {
+		journaldField(&b, "CODE_FUNC", l.Function)
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.conn == nil {
+		conn, err := net.Dial("unixgram", j.path)
+		if err != nil {
+			return err
+		}
+		j.conn = conn
+	}
+	if _, err := j.conn.Write(b.Bytes()); err != nil {
+		j.conn.Close()
+		j.conn = nil
+		return err
+	}
+	return nil
+}
+
+// journaldField adds one KEY=value to a native protocol message,
+// values with a newline use the length prefixed form
+func journaldField(b *bytes.Buffer, key, value string) {
+	b.WriteString(key)
+	if !strings.Contains(value, "\n") {
+		b.WriteByte('=')
+		b.WriteString(value)
+		b.WriteByte('\n')
+		return
+	}
+	b.WriteByte('\n')
+	var n [8]byte
+	binary.LittleEndian.PutUint64(n[:], uint64(len(value)))
+	b.Write(n[:])
+	b.WriteString(value)
+	b.WriteByte('\n')
+}
+
+// priorityFor is the syslog priority for level, a registered level
+// gets the priority of the nearest built in level below it
+func priorityFor(level SnakeLoggerLevel) int {
+	for _, l := range []SnakeLoggerLevel{ReportLevel, ErrorLevel, WarnLevel, InfoLevel} {
+		if !level.below(l) {
+			return journaldPriority[l]
+		}
+	}
+	return journaldPriority[DebugLevel]
+}
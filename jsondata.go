@@ -0,0 +1,33 @@
+package snakeLoggerFile
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// InfoJSON logs msg with obj attached as the "data" field
+// obj is marshalled to JSON straight away, so changing it afterwards is fine
+// JSON output gets it as a real object, text output gets the JSON text
+// if obj can't be marshalled, the field is fmt's %+v of it instead
+func (s *SnakeLogger) InfoJSON(msg string, obj interface{}) {
+	s.logJSON(InfoLevel, msg, obj)
+}
+
+// DebugJSON is InfoJSON at debug level
+func (s *SnakeLogger) DebugJSON(msg string, obj interface{}) {
+	s.logJSON(DebugLevel, msg, obj)
+}
+
+func (s *SnakeLogger) logJSON(level SnakeLoggerLevel, msg string, obj interface{}) {
+	if !s.enabled(level) {
+		return
+	}
+	var data interface{}
+	b, err := json.Marshal(obj)
+	if err != nil {
+		data = fmt.Sprintf("%+v", obj)
+	} else {
+		data = json.RawMessage(b)
+	}
+	s.logFields(level, msg, map[string]interface{}{"data": data})
+}
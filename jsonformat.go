@@ -0,0 +1,161 @@
+package snakeLoggerFile
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// SchemaVersion is written as "v" in every JSON line
+// version 1 has: v, id, sev, msg, timestamp, unix_timestamp, turn,
+// function, snake_name, instance and fields
+// version 2 adds seq
+// it goes up by one whenever a key is renamed, removed or added,
+// so parsers can branch on it
+const SchemaVersion = 2
+
+// jsonKeys is every key a JSON line can have, in the order they are written
+// these are the canonical names used with NewJSONFormatter
+var jsonKeys = []string{"v", "id", "sev", "msg", "timestamp", "unix_timestamp", "turn", "function", "snake_name", "instance", "fields", "seq"}
+
+// JSONFormatter renders each entry as a single line JSON object,
+// so the file can be read as newline delimited JSON
+// every exported field of LogData is a key, empty strings are kept as ""
+// the keys are always in the jsonKeys order and fields are sorted by key,
+// nested maps included, so the same entry always gives the same line
+// the zero value uses the canonical key names
+type JSONFormatter struct {
+	// keys maps canonical key names to the names actually written
+	keys map[string]string
+	// FlattenFields writes each field as a top level key after the usual
+	// ones, sorted, instead of nesting them under "fields". A field named
+	// like one of the usual keys is written as "fields.<name>" instead
+	FlattenFields bool
+}
+
+// NewJSONFormatter returns a JSONFormatter that writes keys under other names,
+// like {"timestamp": "@timestamp", "sev": "severity", "msg": "message"}
+// keys not in the map keep their canonical name
+// an unknown canonical key, or two keys ending up with the same name, is an error
+func NewJSONFormatter(keys map[string]string) (JSONFormatter, error) {
+	known := make(map[string]bool, len(jsonKeys))
+	for _, k := range jsonKeys {
+		known[k] = true
+	}
+	mapped := make(map[string]string, len(keys))
+	for k, v := range keys {
+		if !known[k] {
+			return JSONFormatter{}, fmt.Errorf("unknown json key %q", k)
+		}
+		if v == "" {
+			return JSONFormatter{}, fmt.Errorf("empty output name for json key %q", k)
+		}
+		mapped[k] = v
+	}
+	seen := make(map[string]string, len(jsonKeys))
+	for _, k := range jsonKeys {
+		out := k
+		if v, ok := mapped[k]; ok {
+			out = v
+		}
+		if other, ok := seen[out]; ok {
+			return JSONFormatter{}, fmt.Errorf("json keys %q and %q both write %q", other, k, out)
+		}
+		seen[out] = k
+	}
+	return JSONFormatter{keys: mapped}, nil
+}
+
+// jsonLine puts the schema version in front of the entry
+type jsonLine struct {
+	V int `json:"v"`
+	LogData
+}
+
+// Format implements Formatter
+func (j JSONFormatter) Format(l LogData) []byte {
+	var (
+		b   []byte
+		err error
+	)
+	if len(j.keys) == 0 && !(j.FlattenFields && len(l.Fields) > 0) {
+		b, err = json.Marshal(jsonLine{V: SchemaVersion, LogData: l})
+	} else {
+		b, err = j.marshalMapped(l)
+	}
+	if err != nil {
+		// only a field value that can't be marshalled gets here,
+		// don't lose the line if it does
+		return TextFormatter{}.Format(l)
+	}
+	return append(b, '\n')
+}
+
+// marshalMapped writes the same object as json.Marshal would, with renamed keys
+func (j JSONFormatter) marshalMapped(l LogData) ([]byte, error) {
+	values := map[string]interface{}{
+		"v":              SchemaVersion,
+		"id":             l.ID,
+		"sev":            l.Sev,
+		"msg":            l.Msg,
+		"timestamp":      l.Timestamp,
+		"unix_timestamp": l.UnixTimeStamp,
+		"turn":           l.Turn,
+		"function":       l.Function,
+		"snake_name":     l.SnakeName,
+	}
+	if l.Instance != "" {
+		values["instance"] = l.Instance
+	}
+	if len(l.Fields) > 0 && !j.FlattenFields {
+		values["fields"] = l.Fields
+	}
+	if l.Seq != 0 {
+		values["seq"] = l.Seq
+	}
+
+	var b strings.Builder
+	b.WriteByte('{')
+	written := make(map[string]bool, len(jsonKeys))
+	add := func(name string, v interface{}) error {
+		kb, _ := json.Marshal(name)
+		vb, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		if len(written) > 0 {
+			b.WriteByte(',')
+		}
+		written[name] = true
+		b.Write(kb)
+		b.WriteByte(':')
+		b.Write(vb)
+		return nil
+	}
+	for _, k := range jsonKeys {
+		v, ok := values[k]
+		if !ok {
+			continue
+		}
+		name := k
+		if out, ok := j.keys[k]; ok {
+			name = out
+		}
+		if err := add(name, v); err != nil {
+			return nil, err
+		}
+	}
+	if j.FlattenFields {
+		for _, k := range sortedKeys(l.Fields) {
+			name := k
+			if written[name] {
+				name = "fields." + k
+			}
+			if err := add(name, l.Fields[k]); err != nil {
+				return nil, err
+			}
+		}
+	}
+	b.WriteByte('}')
+	return []byte(b.String()), nil
+}
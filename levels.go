@@ -0,0 +1,163 @@
+package snakeLoggerFile
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// levelTable is every level the package knows, built in or registered
+// a table is never changed once it is in use, RegisterLevel makes a new one
+type levelTable struct {
+	names  map[SnakeLoggerLevel]string
+	byName map[string]SnakeLoggerLevel
+	// order is lowest to highest, NullLevel is always last
+	order []SnakeLoggerLevel
+	// rank is each level's place in order, which is what gating compares
+	rank [256]uint8
+	next SnakeLoggerLevel
+}
+
+func newLevelTable(order []SnakeLoggerLevel, names map[SnakeLoggerLevel]string, next SnakeLoggerLevel) *levelTable {
+	t := &levelTable{
+		names:  names,
+		byName: make(map[string]SnakeLoggerLevel, len(names)),
+		order:  order,
+		next:   next,
+	}
+	for l, n := range names {
+		t.byName[n] = l
+	}
+	for i, l := range order {
+		t.rank[l] = uint8(i)
+	}
+	return t
+}
+
+var (
+	builtinLevels = newLevelTable(
+		[]SnakeLoggerLevel{DebugLevel, InfoLevel, WarnLevel, ErrorLevel, ReportLevel, NullLevel},
+		levelMap, NullLevel+1)
+	levelsMu sync.Mutex
+	// registeredLevels holds a *levelTable once RegisterLevel has been called
+	registeredLevels atomic.Value
+)
+
+func levels() *levelTable {
+	if t, ok := registeredLevels.Load().(*levelTable); ok {
+		return t
+	}
+	return builtinLevels
+}
+
+// RegisterLevel adds a named level that sorts just above after,
+// and above any levels registered there before it, so RegisterLevel("security", ErrorLevel) gives a level that gets
+// through a logger at error but not one at report.
+// A name already in use, built in or registered, is an error, as is
+// registering above NullLevel, which always silences everything.
+// Register levels at startup, before loggers use them
+func RegisterLevel(name string, after SnakeLoggerLevel) (SnakeLoggerLevel, error) {
+	levelsMu.Lock()
+	defer levelsMu.Unlock()
+	t := levels()
+	if name == "" {
+		return 0, fmt.Errorf("empty level name")
+	}
+	if l, ok := t.byName[name]; ok {
+		return 0, fmt.Errorf("level name %q is already used by level %d", name, l)
+	}
+	if after == NullLevel {
+		return 0, fmt.Errorf("cannot register level %q above null", name)
+	}
+	pos := -1
+	for i, l := range t.order {
+		if l == after {
+			pos = i
+		}
+	}
+	if pos < 0 {
+		return 0, fmt.Errorf("cannot register level %q after unknown level %d", name, after)
+	}
+	if t.next == 0 {
+		return 0, fmt.Errorf("no room to register level %q", name)
+	}
+	level := t.next
+	// stay above levels already registered there, they were first
+	for pos+1 < len(t.order) && t.order[pos+1] > NullLevel {
+		pos++
+	}
+	order := make([]SnakeLoggerLevel, 0, len(t.order)+1)
+	order = append(order, t.order[:pos+1]...)
+	order = append(order, level)
+	order = append(order, t.order[pos+1:]...)
+	names := make(map[SnakeLoggerLevel]string, len(t.names)+1)
+	for l, n := range t.names {
+		names[l] = n
+	}
+	names[level] = name
+	registeredLevels.Store(newLevelTable(order, names, level+1))
+	return level, nil
+}
+
+// levelName is the name written as Sev for l
+func levelName(l SnakeLoggerLevel) string {
+	return levels().names[l]
+}
+
+// levelByName is the reverse of levelName
+func levelByName(name string) (SnakeLoggerLevel, bool) {
+	l, ok := levels().byName[name]
+	return l, ok
+}
+
+// below says if l sorts lower than min, which is what every level gate checks
+func (l SnakeLoggerLevel) below(min SnakeLoggerLevel) bool {
+	t := levels()
+	return t.rank[l] < t.rank[min]
+}
+
+// String is the level's name, or its number if it has none
+func (l SnakeLoggerLevel) String() string {
+	if n := levelName(l); n != "" {
+		return n
+	}
+	return fmt.Sprintf("level(%d)", uint8(l))
+}
+
+// Log writes m at any level, including ones from RegisterLevel
+func (s *SnakeLogger) Log(level SnakeLoggerLevel, m string) {
+	if !s.enabled(level) {
+		return
+	}
+	now := time.Now()
+	s.send(level, m, now, callFields(level))
+}
+
+// Logf is Log with formatting
+func (s *SnakeLogger) Logf(level SnakeLoggerLevel, format string, v ...interface{}) {
+	if !s.enabled(level) {
+		return
+	}
+	now := time.Now()
+	msg := fmt.Sprintf(format, v...)
+	s.send(level, msg, now, callFields(level))
+}
+
+// LogAt is Log with the entry's time given, for replaying recorded games
+// t is used for Timestamp and UnixTimeStamp instead of the current time
+func (s *SnakeLogger) LogAt(level SnakeLoggerLevel, t time.Time, m string) {
+	if !s.enabled(level) {
+		return
+	}
+	s.send(level, m, t, callFields(level))
+}
+
+// LogAtf is LogAt with formatting
+func (s *SnakeLogger) LogAtf(level SnakeLoggerLevel, t time.Time, format string, v ...interface{}) {
+	if !s.enabled(level) {
+		return
+	}
+	msg := fmt.Sprintf(format, v...)
+	s.send(level, msg, t, callFields(level))
+}
@@ -0,0 +1,270 @@
+package snakeLoggerFile
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MemFS is an in memory FileSystem, handy for tests that want to
+// check what was written without touching $HOME, this package's own
+// tests run on one. FailOpen and OpenCount help test a broken disk
+// and leaked handles
+type MemFS struct {
+	mu    sync.Mutex
+	files map[string]*memData
+	dirs  map[string]struct{}
+	// links maps a symlink to its target, relative targets are from the link's dir
+	links map[string]string
+	// open counts handles not closed yet
+	open int
+	// failOpen is set by FailOpen
+	failOpen func(name string) error
+}
+
+// memData is the contents of one MemFS file
+type memData struct {
+	buf     bytes.Buffer
+	modTime time.Time
+}
+
+// NewMemFS returns an empty MemFS
+func NewMemFS() *MemFS {
+	return &MemFS{
+		files: map[string]*memData{},
+		dirs:  map[string]struct{}{},
+		links: map[string]string{},
+	}
+}
+
+// OpenFile implements FileSystem
+// only O_CREATE, O_EXCL and O_TRUNC change anything, writes always append
+func (m *MemFS) OpenFile(name string, flag int, perm os.FileMode) (File, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	name = m.resolve(name)
+	if m.failOpen != nil {
+		if err := m.failOpen(name); err != nil {
+			return nil, &os.PathError{Op: "open", Path: name, Err: err}
+		}
+	}
+	d, ok := m.files[name]
+	switch {
+	case ok && flag&os.O_CREATE != 0 && flag&os.O_EXCL != 0:
+		return nil, &os.PathError{Op: "open", Path: name, Err: os.ErrExist}
+	case !ok && flag&os.O_CREATE == 0:
+		return nil, &os.PathError{Op: "open", Path: name, Err: os.ErrNotExist}
+	case !ok:
+		d = &memData{modTime: time.Now()}
+		m.files[name] = d
+	case flag&os.O_TRUNC != 0:
+		d.buf.Reset()
+		d.modTime = time.Now()
+	}
+	m.open++
+	return &memFile{fs: m, data: d}, nil
+}
+
+// OpenCount is how many files opened with OpenFile haven't been closed
+func (m *MemFS) OpenCount() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.open
+}
+
+// FailOpen makes OpenFile fail with the error fn gives for a name,
+// to test a disk that won't open a file. fn returning nil lets the
+// open go ahead, and a nil fn turns it off
+func (m *MemFS) FailOpen(fn func(name string) error) {
+	m.mu.Lock()
+	m.failOpen = fn
+	m.mu.Unlock()
+}
+
+// Open implements FileSystem, the reader sees the file as it was when opened
+func (m *MemFS) Open(name string) (io.ReadCloser, error) {
+	b, err := m.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	return io.NopCloser(bytes.NewReader(b)), nil
+}
+
+// MkdirAll implements FileSystem
+func (m *MemFS) MkdirAll(dir string, perm os.FileMode) error {
+	m.mu.Lock()
+	m.dirs[dir] = struct{}{}
+	m.mu.Unlock()
+	return nil
+}
+
+// Remove implements FileSystem
+func (m *MemFS) Remove(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.links[name]; ok {
+		delete(m.links, name)
+		return nil
+	}
+	if _, ok := m.files[name]; !ok {
+		return &os.PathError{Op: "remove", Path: name, Err: os.ErrNotExist}
+	}
+	delete(m.files, name)
+	return nil
+}
+
+// Rename implements FileSystem
+func (m *MemFS) Rename(oldpath, newpath string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if target, ok := m.links[oldpath]; ok {
+		delete(m.links, oldpath)
+		delete(m.files, newpath)
+		m.links[newpath] = target
+		return nil
+	}
+	d, ok := m.files[oldpath]
+	if !ok {
+		return &os.LinkError{Op: "rename", Old: oldpath, New: newpath, Err: os.ErrNotExist}
+	}
+	delete(m.files, oldpath)
+	delete(m.links, newpath)
+	m.files[newpath] = d
+	return nil
+}
+
+// Symlink implements FileSystem
+func (m *MemFS) Symlink(oldname, newname string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, isFile := m.files[newname]
+	_, isLink := m.links[newname]
+	if isFile || isLink {
+		return &os.LinkError{Op: "symlink", Old: oldname, New: newname, Err: os.ErrExist}
+	}
+	m.links[newname] = oldname
+	return nil
+}
+
+// resolve follows name if it is a symlink, m.mu must be held
+func (m *MemFS) resolve(name string) string {
+	target, ok := m.links[name]
+	if !ok {
+		return name
+	}
+	if !path.IsAbs(target) {
+		target = path.Join(path.Dir(name), target)
+	}
+	return target
+}
+
+// Stat implements FileSystem
+func (m *MemFS) Stat(name string) (os.FileInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	d, ok := m.files[m.resolve(name)]
+	if !ok {
+		return nil, &os.PathError{Op: "stat", Path: name, Err: os.ErrNotExist}
+	}
+	return memInfo{name: path.Base(name), size: int64(d.buf.Len()), modTime: d.modTime}, nil
+}
+
+// Lstat implements FileSystem
+func (m *MemFS) Lstat(name string) (os.FileInfo, error) {
+	m.mu.Lock()
+	_, ok := m.links[name]
+	m.mu.Unlock()
+	if ok {
+		return memInfo{name: path.Base(name), mode: os.ModeSymlink | 0777}, nil
+	}
+	return m.Stat(name)
+}
+
+// ReadDir implements FileSystem, symlinks are left out
+func (m *MemFS) ReadDir(dir string) ([]os.FileInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var res []os.FileInfo
+	for name, d := range m.files {
+		if path.Dir(name) == path.Clean(dir) {
+			res = append(res, memInfo{name: path.Base(name), size: int64(d.buf.Len()), modTime: d.modTime})
+		}
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Name() < res[j].Name() })
+	return res, nil
+}
+
+// ReadFile returns a copy of everything written to name
+func (m *MemFS) ReadFile(name string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	d, ok := m.files[m.resolve(name)]
+	if !ok {
+		return nil, &os.PathError{Op: "read", Path: name, Err: os.ErrNotExist}
+	}
+	return append([]byte(nil), d.buf.Bytes()...), nil
+}
+
+// Files lists every file in the MemFS, sorted
+func (m *MemFS) Files() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	res := make([]string, 0, len(m.files))
+	for name := range m.files {
+		res = append(res, name)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// memFile is an open handle on a MemFS file
+type memFile struct {
+	fs     *MemFS
+	data   *memData
+	closed bool
+}
+
+func (f *memFile) Write(p []byte) (int, error) {
+	f.fs.mu.Lock()
+	defer f.fs.mu.Unlock()
+	if f.closed {
+		return 0, os.ErrClosed
+	}
+	f.data.modTime = time.Now()
+	return f.data.buf.Write(p)
+}
+
+func (f *memFile) Close() error {
+	f.fs.mu.Lock()
+	defer f.fs.mu.Unlock()
+	if f.closed {
+		return os.ErrClosed
+	}
+	f.closed = true
+	f.fs.open--
+	return nil
+}
+
+// memInfo is the os.FileInfo for a MemFS file
+type memInfo struct {
+	name    string
+	size    int64
+	modTime time.Time
+	mode    os.FileMode
+}
+
+func (i memInfo) Name() string { return i.name }
+func (i memInfo) Size() int64  { return i.size }
+func (i memInfo) Mode() os.FileMode {
+	if i.mode == 0 {
+		return 0644
+	}
+	return i.mode
+}
+func (i memInfo) ModTime() time.Time { return i.modTime }
+func (i memInfo) IsDir() bool        { return false }
+func (i memInfo) Sys() interface{}   { return nil }
@@ -0,0 +1,65 @@
+package snakeLoggerFile
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestMemFS(t *testing.T) {
+	m := NewMemFS()
+	write := func(name string, flag int, data string) {
+		t.Helper()
+		f, err := m.OpenFile(name, flag, 0644)
+		if err != nil {
+			t.Fatalf("open %s: %v", name, err)
+		}
+		if _, err := f.Write([]byte(data)); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+		f.Close()
+	}
+	read := func(name string) string {
+		t.Helper()
+		b, err := m.ReadFile(name)
+		if err != nil {
+			t.Fatalf("read %s: %v", name, err)
+		}
+		return string(b)
+	}
+
+	appendFlags := os.O_APPEND | os.O_CREATE | os.O_WRONLY
+	write("/d/a.log", appendFlags, "one\n")
+	write("/d/a.log", appendFlags, "two\n")
+	if got := read("/d/a.log"); got != "one\ntwo\n" {
+		t.Errorf("after two appends a.log = %q", got)
+	}
+	write("/d/a.log", os.O_CREATE|os.O_TRUNC|os.O_WRONLY, "new\n")
+	if got := read("/d/a.log"); got != "new\n" {
+		t.Errorf("after O_TRUNC a.log = %q", got)
+	}
+	if _, err := m.OpenFile("/d/a.log", os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644); !errors.Is(err, os.ErrExist) {
+		t.Errorf("O_EXCL on an existing file: %v", err)
+	}
+	if _, err := m.OpenFile("/d/missing.log", os.O_WRONLY, 0644); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("opening a missing file without O_CREATE: %v", err)
+	}
+
+	write("/d/b.log", appendFlags, "b\n")
+	if got := m.Files(); !reflect.DeepEqual(got, []string{"/d/a.log", "/d/b.log"}) {
+		t.Errorf("Files = %v", got)
+	}
+	if err := m.Remove("/d/a.log"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Remove("/d/a.log"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("removing a removed file: %v", err)
+	}
+
+	f, _ := m.OpenFile("/d/b.log", appendFlags, 0644)
+	f.Close()
+	if _, err := f.Write([]byte("late")); !errors.Is(err, os.ErrClosed) {
+		t.Errorf("write after close: %v", err)
+	}
+}
@@ -0,0 +1,41 @@
+package snakeLoggerFile
+
+import "sync"
+
+// Observer is called with every entry the writer handles
+type Observer func(l LogData)
+
+var (
+	observersMu  sync.RWMutex
+	observers    = map[int]Observer{}
+	nextObserver int
+)
+
+// AddObserver calls fn with each entry once the sinks have written it
+// the files keep being written as normal, so an integration test can
+// check entries while still getting real output
+// fn runs on the writer goroutine and should be quick, it must never
+// log through this package: the entry would queue behind the one fn is
+// handling, and with a full queue the writer waits on itself for good
+// call the returned func to stop observing
+func AddObserver(fn Observer) (remove func()) {
+	observersMu.Lock()
+	id := nextObserver
+	nextObserver++
+	observers[id] = fn
+	observersMu.Unlock()
+	return func() {
+		observersMu.Lock()
+		delete(observers, id)
+		observersMu.Unlock()
+	}
+}
+
+// notifyObservers is called by the writer after dispatch
+func notifyObservers(m LogData) {
+	observersMu.RLock()
+	defer observersMu.RUnlock()
+	for _, fn := range observers {
+		fn(m)
+	}
+}
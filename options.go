@@ -0,0 +1,63 @@
+package snakeLoggerFile
+
+import "time"
+
+// Option sets something on a logger made by NewLoggerWithOptions
+type Option func(s *SnakeLogger)
+
+// NewLoggerWithOptions is NewLogger with everything else set up front
+//
+//	logger := NewLoggerWithOptions("debug", WithName("mysnake"), WithIndex(3))
+func NewLoggerWithOptions(level string, opts ...Option) *SnakeLogger {
+	s := NewLogger(level)
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// WithName sets the snake name, same as UpdateName
+func WithName(name string) Option {
+	return func(s *SnakeLogger) {
+		s.name = name
+	}
+}
+
+// WithID sets the log id, same as UpdateID
+func WithID(id string) Option {
+	return func(s *SnakeLogger) {
+		s.id = id
+	}
+}
+
+// WithIndex sets the logger index, same as UpdateIndex
+func WithIndex(i uint64) Option {
+	return func(s *SnakeLogger) {
+		s.index = i
+	}
+}
+
+// WithFormatter sets the output format for the logger, same as UpdateFormatter
+func WithFormatter(f Formatter) Option {
+	return func(s *SnakeLogger) {
+		s.formatter = f
+	}
+}
+
+// WithStaticFields adds fields like region or cluster to every entry the
+// logger makes for its whole life, children included
+// fields from WithField or a helper win when they use the same key
+// the map is copied
+func WithStaticFields(fields map[string]interface{}) Option {
+	return func(s *SnakeLogger) {
+		s.staticFields = mergeFields(s.staticFields, fields)
+	}
+}
+
+// WithElapsed adds an elapsed_ms field to every entry, counted from when
+// the logger is made, see StartElapsed
+func WithElapsed() Option {
+	return func(s *SnakeLogger) {
+		s.start = time.Now()
+	}
+}
@@ -0,0 +1,83 @@
+package snakeLoggerFile
+
+import (
+	"reflect"
+	"runtime"
+	"strings"
+	"sync/atomic"
+)
+
+var captureOrigin int32
+
+// SetOriginPackage adds a "package" field with the Go package that made
+// each log call, the last element of its import path
+// it is off by default because looking up the caller costs a stack walk
+func SetOriginPackage(on bool) {
+	var v int32
+	if on {
+		v = 1
+	}
+	atomic.StoreInt32(&captureOrigin, v)
+}
+
+// ownPrefix is how this package's functions start in a stack trace
+var ownPrefix = funcPackage(runtime.FuncForPC(reflect.ValueOf(funcPackage).Pointer()).Name()) + "."
+
+// funcPackage is the import path part of a function name from runtime,
+// like github.com/a/b for github.com/a/b.(*T).Method
+func funcPackage(name string) string {
+	slash := strings.LastIndex(name, "/")
+	if dot := strings.Index(name[slash+1:], "."); dot >= 0 {
+		return name[:slash+1+dot]
+	}
+	return name
+}
+
+// originFields returns the package field for the first caller outside
+// this package, so it doesn't matter how many helpers a log call goes through
+// like stackFields it has to run on the caller's goroutine
+func originFields() map[string]interface{} {
+	if atomic.LoadInt32(&captureOrigin) == 0 {
+		return nil
+	}
+	var pcs [16]uintptr
+	n := runtime.Callers(2, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		f, more := frames.Next()
+		if f.Function != "" && !strings.HasPrefix(f.Function, ownPrefix) {
+			return packageField(f.Function)
+		}
+		if !more {
+			return nil
+		}
+	}
+}
+
+// originFieldsPC is originFields for a caller already known by its pc,
+// like the one a slog.Record carries, 0 gives nothing
+func originFieldsPC(pc uintptr) map[string]interface{} {
+	if pc == 0 || atomic.LoadInt32(&captureOrigin) == 0 {
+		return nil
+	}
+	f, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	if f.Function == "" {
+		return nil
+	}
+	return packageField(f.Function)
+}
+
+// packageField is the package field for the function named fn
+func packageField(fn string) map[string]interface{} {
+	pkg := funcPackage(fn)
+	return map[string]interface{}{"package": pkg[strings.LastIndex(pkg, "/")+1:]}
+}
+
+// callFields is everything captured at the call site for an entry at level
+func callFields(level SnakeLoggerLevel) map[string]interface{} {
+	origin := originFields()
+	if origin == nil {
+		return stackFields(level)
+	}
+	return mergeFields(stackFields(level), origin)
+}
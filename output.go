@@ -0,0 +1,73 @@
+package snakeLoggerFile
+
+import "io"
+
+// SetOutput sends what would go to the log files to w instead,
+// nil goes back to the files. It can be changed at any time:
+// everything logged before the call is written to the old output,
+// which is flushed (if it has a Flush() error method) before the switch,
+// and everything logged after the call returns goes to w.
+// Entries still being handed to the writer by a log call running at the
+// same time may land on either side. It must not be called from a sink or observer
+// a SetBackupDir backup keeps writing its files while w is set
+func SetOutput(w io.Writer) {
+	var out *WriterSink
+	if w != nil {
+		out = NewWriterSink(w)
+	}
+	runOnWriter(func() {
+		fs := defaultFileSink
+		var err error
+		if fs.output != nil {
+			err = fs.output.flush()
+		} else {
+			err = fs.Flush()
+		}
+		if err != nil {
+			recordError(err)
+		}
+		fs.mu.Lock()
+		fs.output = out
+		fs.mu.Unlock()
+	})
+}
+
+// SetOutputFactory sends each snake's entries to the writer fn returns for
+// its name ("" for the generic log) instead of its file, fn is called once
+// per name and the writer kept. Like SetOutput it takes effect in order
+// with the entries around it, nil goes back to the files
+// SetOutput wins while both are set
+func SetOutputFactory(fn func(snakeName string) io.Writer) {
+	runOnWriter(func() {
+		fs := defaultFileSink
+		fs.mu.Lock()
+		defer fs.mu.Unlock()
+		for _, ws := range fs.outputs {
+			if err := ws.flush(); err != nil {
+				recordError(err)
+			}
+		}
+		fs.outputFn = fn
+		fs.outputs = map[string]*WriterSink{}
+	})
+}
+
+// factoryOutput is the writer for snakeName from SetOutputFactory, fs.mu must be held
+func (fs *fileSink) factoryOutput(snakeName string) *WriterSink {
+	ws, ok := fs.outputs[snakeName]
+	if !ok {
+		ws = NewWriterSink(fs.outputFn(snakeName))
+		fs.outputs[snakeName] = ws
+	}
+	return ws
+}
+
+// flush flushes the underlying writer if it buffers
+func (ws *WriterSink) flush() error {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	if f, ok := ws.w.(flusher); ok {
+		return f.Flush()
+	}
+	return nil
+}
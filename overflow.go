@@ -0,0 +1,56 @@
+package snakeLoggerFile
+
+import (
+	"os"
+	"sync/atomic"
+)
+
+// OverflowPolicy is what a log call does when the writer's queue is full
+type OverflowPolicy int32
+
+const (
+	// Block waits for room in the queue, nothing is lost but a slow disk slows the snake
+	Block OverflowPolicy = iota
+	// DropNewest throws away the entry that didn't fit, see Dropped
+	DropNewest
+	// Stderr writes the entry that didn't fit straight to stderr instead,
+	// still redacted
+	Stderr
+)
+
+var (
+	overflowPolicy int32
+	dropped        uint64
+)
+
+// SetOverflowPolicy picks what happens when a log call finds the queue full
+// the default is Block
+func SetOverflowPolicy(p OverflowPolicy) {
+	atomic.StoreInt32(&overflowPolicy, int32(p))
+}
+
+// Dropped is how many entries DropNewest (or a failed Stderr write) has thrown away
+func Dropped() uint64 {
+	return atomic.LoadUint64(&dropped)
+}
+
+// enqueue hands m to the writer, following the overflow policy if the queue is full
+func enqueue(m LogData) {
+	p := OverflowPolicy(atomic.LoadInt32(&overflowPolicy))
+	if p == Block {
+		writeChan <- m
+		return
+	}
+	select {
+	case writeChan <- m:
+		return
+	default:
+	}
+	if p == Stderr {
+		// the writer never sees m, so it is fixed and redacted here
+		if _, err := os.Stderr.Write(format(redactEntry(fixEntry(m)))); err == nil {
+			return
+		}
+	}
+	atomic.AddUint64(&dropped, 1)
+}
@@ -0,0 +1,56 @@
+package snakeLoggerFile
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestStderrOverflowIsRedacted(t *testing.T) {
+	if err := SetRedaction(Redaction{Keys: []string{"password"}, Patterns: []string{`token=\w+`}}); err != nil {
+		t.Fatal(err)
+	}
+	defer SetRedaction(Redaction{})
+	SetOverflowPolicy(Stderr)
+	defer SetOverflowPolicy(Block)
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stderr := os.Stderr
+	os.Stderr = w
+	defer func() { os.Stderr = stderr }()
+
+	// hold the writer and fill its queue so the next entry overflows
+	release := make(chan struct{})
+	held := make(chan struct{})
+	go runOnWriter(func() {
+		close(held)
+		<-release
+	})
+	<-held
+	for len(writeChan) < cap(writeChan) {
+		writeChan <- LogData{op: func() {}}
+	}
+	NewLogger("debug").ToSnake("overflow").
+		WithField("password", "hunter2").Info("login token=abc123")
+	close(release)
+	w.Close()
+	os.Stderr = stderr
+
+	out, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(out)
+	if !strings.Contains(got, "login") {
+		t.Fatalf("entry didn't overflow to stderr, got %q", got)
+	}
+	for _, secret := range []string{"hunter2", "abc123"} {
+		if strings.Contains(got, secret) {
+			t.Errorf("stderr has %q: %s", secret, got)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package snakeLoggerFile
+
+import "sync"
+
+// LogData goes through the channel by value, so each sender and the writer
+// have their own copy and a log call allocates no LogData of its own
+// the LogData the heap does see are the ones the writer collects from
+// collapse, those and the line buffers the sinks format into are pooled
+//
+// ownership of a pooled buffer: a sink takes one with getBuffer, formats
+// into it, writes it and gives it back with putBuffer before Write returns
+// nothing else may keep the bytes, so a sink that queues work (like RetrySink)
+// queues the LogData and formats when it actually sends
+//
+// ownership of pooled entries: only writeEntry takes them, with getEntries,
+// and it gives them back with putEntries once every sink and observer has
+// returned. sinks and observers get copies, so none of them can see an
+// entry after it goes back
+var bufferPool = sync.Pool{
+	New: func() interface{} {
+		b := make([]byte, 0, 256)
+		return &b
+	},
+}
+
+// maxPooledBuffer keeps one huge line from pinning a big buffer forever
+const maxPooledBuffer = 64 * 1024
+
+func getBuffer() *[]byte {
+	return bufferPool.Get().(*[]byte)
+}
+
+// putBuffer returns b, the last thing formatted into bp, to the pool
+func putBuffer(bp *[]byte, b []byte) {
+	if cap(b) > maxPooledBuffer {
+		return
+	}
+	*bp = b[:0]
+	bufferPool.Put(bp)
+}
+
+// entryPool holds the LogData slices writeEntry collects from collapse
+var entryPool = sync.Pool{
+	New: func() interface{} {
+		e := make([]LogData, 0, 2)
+		return &e
+	},
+}
+
+func getEntries() *[]LogData {
+	return entryPool.Get().(*[]LogData)
+}
+
+// putEntries returns e, the last thing collected into ep, to the pool
+// the entries are cleared so the pool doesn't keep their fields alive
+func putEntries(ep *[]LogData, e []LogData) {
+	for i := range e {
+		e[i] = LogData{}
+	}
+	*ep = e[:0]
+	entryPool.Put(ep)
+}
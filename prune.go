@@ -0,0 +1,112 @@
+package snakeLoggerFile
+
+import (
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	pruneMu   sync.Mutex
+	pruneStop chan struct{}
+	// pruneBudget is the current budget, 0 when off
+	pruneBudget int64
+)
+
+// SetMaxDirSize keeps the log directory under budget bytes by deleting
+// the oldest .log and .log.gz files, checked every interval (default one minute)
+// files still being written this session are never deleted, so the
+// directory can stay over budget if those alone are bigger
+// a budget of 0 or less turns it off
+func SetMaxDirSize(budget int64, interval time.Duration) {
+	pruneMu.Lock()
+	defer pruneMu.Unlock()
+	if pruneStop != nil {
+		close(pruneStop)
+		pruneStop = nil
+	}
+	pruneBudget = 0
+	if budget <= 0 {
+		return
+	}
+	if interval <= 0 {
+		interval = defaultSweepInterval
+	}
+	pruneBudget = budget
+	pruneStop = make(chan struct{})
+	go pruneLoop(defaultFileSink, budget, interval, pruneStop)
+}
+
+func pruneLoop(fs *fileSink, budget int64, interval time.Duration, stop chan struct{}) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-t.C:
+			if dir := fs.dir(); dir != "" {
+				prune(currentFileSystem(), dir, budget, fs.activeFiles())
+			}
+		}
+	}
+}
+
+// prune deletes the oldest log files in dir until it is under budget
+// files in skip are left alone but still count towards the total
+func prune(fsys FileSystem, dir string, budget int64, skip map[string]struct{}) {
+	infos, err := fsys.ReadDir(dir)
+	if err != nil {
+		recordError(err)
+		return
+	}
+	var total int64
+	var candidates []os.FileInfo
+	for _, info := range infos {
+		if !info.Mode().IsRegular() {
+			continue
+		}
+		total += info.Size()
+		name := info.Name()
+		if _, ok := skip[path.Join(dir, name)]; ok {
+			continue
+		}
+		if strings.HasSuffix(name, ".log") || strings.HasSuffix(name, ".log.gz") {
+			candidates = append(candidates, info)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].ModTime().Before(candidates[j].ModTime())
+	})
+	for _, info := range candidates {
+		if total <= budget {
+			return
+		}
+		if err := fsys.Remove(path.Join(dir, info.Name())); err != nil {
+			recordError(err)
+			continue
+		}
+		total -= info.Size()
+	}
+}
+
+// activeFiles is every file this session is writing to or has written,
+// including the segments behind symlinks
+func (fs *fileSink) activeFiles() map[string]struct{} {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	res := make(map[string]struct{}, len(fs.written)+len(fs.open)+len(fs.segments))
+	for name := range fs.written {
+		res[name] = struct{}{}
+	}
+	for name := range fs.open {
+		res[name] = struct{}{}
+	}
+	for _, target := range fs.segments {
+		res[target] = struct{}{}
+	}
+	return res
+}
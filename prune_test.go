@@ -0,0 +1,31 @@
+package snakeLoggerFile
+
+import (
+	"os"
+	"testing"
+)
+
+// memWrite puts data in name, failing the test if it can't
+func memWrite(t *testing.T, fsys *MemFS, name, data string) {
+	t.Helper()
+	f, err := fsys.OpenFile(name, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Write([]byte(data))
+	f.Close()
+}
+
+func TestPruneSkipsActiveWithTrailingSlash(t *testing.T) {
+	fsys := NewMemFS()
+	memWrite(t, fsys, "/x/active.log", "still being written\n")
+	memWrite(t, fsys, "/x/old.log", "old\n")
+
+	prune(fsys, "/x/", 0, map[string]struct{}{"/x/active.log": {}})
+	if _, err := fsys.Stat("/x/active.log"); err != nil {
+		t.Errorf("the active file was pruned: %v", err)
+	}
+	if _, err := fsys.Stat("/x/old.log"); err == nil {
+		t.Error("old.log is still there with a budget of 0")
+	}
+}
@@ -0,0 +1,127 @@
+package snakeLoggerFile
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// defaultQueueSize is how many entries can wait for the writer
+// before log calls start to block
+const defaultQueueSize = 1024
+
+// QueueLen is how many entries are waiting for the writer right now
+// a value near QueueCap means the writer isn't keeping up
+func QueueLen() int {
+	return len(writeChan)
+}
+
+// QueueCap is how many entries can wait for the writer
+func QueueCap() int {
+	return cap(writeChan)
+}
+
+// runOnWriter runs fn on the writer goroutine once everything already on
+// the channel has been written, and waits for it to finish
+// it must not be called from the writer goroutine (a sink or observer)
+// after Close fn is not run at all
+func runOnWriter(fn func()) {
+	gen, ok := beginSend()
+	if !ok {
+		return
+	}
+	defer endSend(gen)
+	done := make(chan struct{})
+	writeChan <- LogData{op: func() {
+		fn()
+		close(done)
+	}}
+	<-done
+}
+
+// backpressure is the state behind SetBackpressureHandler
+type backpressure struct {
+	high, low int
+	fn        func(depth int)
+	// events takes depths to the goroutine calling fn, in order
+	events chan int
+	stop   chan struct{}
+	// above is only touched by the writer goroutine
+	above bool
+}
+
+var (
+	bpMu sync.Mutex
+	// bp holds a *backpressure, nil when there is no handler
+	bp atomic.Value
+)
+
+// SetBackpressureHandler calls fn whenever the writer sees the queue
+// reach high entries, and again once it has drained back to low,
+// so sustained lag can be logged or alerted on without polling QueueLen
+// fn gets the depth at the time and runs on its own goroutine, one call
+// at a time in order, so it is free to log. A nil fn turns it off
+func SetBackpressureHandler(high, low int, fn func(depth int)) {
+	bpMu.Lock()
+	defer bpMu.Unlock()
+	if old, _ := bp.Load().(*backpressure); old != nil {
+		close(old.stop)
+	}
+	if fn == nil {
+		bp.Store((*backpressure)(nil))
+		return
+	}
+	b := &backpressure{
+		high:   high,
+		low:    low,
+		fn:     fn,
+		events: make(chan int, 16),
+		stop:   make(chan struct{}),
+	}
+	go b.run()
+	bp.Store(b)
+}
+
+func (b *backpressure) run() {
+	for {
+		select {
+		case <-b.stop:
+			return
+		case depth := <-b.events:
+			b.fn(depth)
+		}
+	}
+}
+
+// checkBackpressure is called by the writer with the queue depth after each entry
+func checkBackpressure(depth int) {
+	b, _ := bp.Load().(*backpressure)
+	if b == nil {
+		return
+	}
+	switch {
+	case !b.above && depth >= b.high:
+		b.above = true
+	case b.above && depth <= b.low:
+		b.above = false
+	default:
+		return
+	}
+	select {
+	case b.events <- depth:
+	default:
+		// fn is stuck, don't hold up the writer for it
+	}
+}
+
+// Sync waits until everything already on the queue has been written,
+// then flushes every sink and returns the first error from that
+// entries still being handed to the writer by their log call may be missed
+// it must not be called from a sink or observer
+func Sync() error {
+	var err error
+	runOnWriter(func() {
+		flushRepeats("")
+		err = flushSinks()
+	})
+	return err
+}
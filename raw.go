@@ -0,0 +1,30 @@
+package snakeLoggerFile
+
+import (
+	"bytes"
+	"time"
+)
+
+// Raw writes b to the snake's file exactly as given, nothing is formatted
+// it still goes through the writer, so it stays in line with other entries
+// b is copied, so the caller can reuse it straight away
+// Raw ignores the logger level unless the logger is null,
+// sinks with their own level treat it as info
+func (s *SnakeLogger) Raw(b []byte) {
+	if s.getLogLevel() == NullLevel || isDryRun() {
+		return
+	}
+	now := time.Now()
+	m := s.newEntry(InfoLevel, string(bytes.TrimRight(b, "\n")), now, nil)
+	m.raw = append([]byte(nil), b...)
+	if s.rec != nil {
+		s.rec.add(m)
+		return
+	}
+	gen, ok := beginSend()
+	if !ok {
+		return
+	}
+	defer endSend(gen)
+	enqueue(m)
+}
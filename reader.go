@@ -0,0 +1,178 @@
+package snakeLoggerFile
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// maxLineSize is the longest line a LogReader will read
+const maxLineSize = 1024 * 1024
+
+// LogReader reads entries back out of a snake's log file
+// use it like bufio.Scanner:
+//
+//	r, err := OpenLogReader("mysnake")
+//	...
+//	defer r.Close()
+//	for r.Scan() {
+//		entry := r.Entry()
+//	}
+//	err = r.Err()
+type LogReader struct {
+	rc      io.ReadCloser
+	scanner *bufio.Scanner
+	entry   LogData
+	err     error
+}
+
+// OpenLogReader opens the log file for snakeName, "" is the generic log
+// both text and JSON lines are understood, a file can have a mix of them
+// it only reads the current file, not ones that have been rotated
+// the file is flushed first (see FlushFor) so everything logged before
+// the call is there, entries written after it is opened may not be seen
+// it must not be called from a sink or observer
+func OpenLogReader(snakeName string) (*LogReader, error) {
+	if err := FlushFor(snakeName); err != nil {
+		return nil, err
+	}
+	rc, err := currentFileSystem().Open(defaultFileSink.pathFor(snakeName))
+	if err != nil {
+		return nil, err
+	}
+	return NewLogReader(rc), nil
+}
+
+// NewLogReader reads entries from any log data, like a rotated file
+func NewLogReader(rc io.ReadCloser) *LogReader {
+	sc := bufio.NewScanner(rc)
+	sc.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	return &LogReader{rc: rc, scanner: sc}
+}
+
+// Scan moves to the next entry, false at the end or on a read error
+// a line that isn't a recognised format (like one from Raw) comes back
+// as an entry with only Msg set
+func (r *LogReader) Scan() bool {
+	for r.scanner.Scan() {
+		line := r.scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		l, err := ParseLogLine(line)
+		if err != nil {
+			l = LogData{Msg: string(line)}
+		}
+		r.entry = l
+		return true
+	}
+	r.err = r.scanner.Err()
+	return false
+}
+
+// Entry is the entry found by the last Scan
+func (r *LogReader) Entry() LogData {
+	return r.entry
+}
+
+// Err is the read error that stopped Scan, nil at the end of the file
+func (r *LogReader) Err() error {
+	return r.err
+}
+
+// Close closes the file
+func (r *LogReader) Close() error {
+	return r.rc.Close()
+}
+
+// errNotLogLine is returned by ParseLogLine for lines it doesn't recognise
+var errNotLogLine = errors.New("not a log line")
+
+// ParseLogLine turns one line written by TextFormatter or JSONFormatter back into a LogData
+// for text lines, fields can't be told apart from the message, so they stay in Msg
+// JSON lines have to use the canonical key names
+func ParseLogLine(line []byte) (LogData, error) {
+	line = bytes.TrimRight(line, "\r\n")
+	if len(line) > 0 && line[0] == '{' {
+		var jl jsonLine
+		if err := json.Unmarshal(line, &jl); err != nil {
+			return LogData{}, err
+		}
+		jl.LogData.Level = levelFromName(jl.LogData.Sev)
+		return jl.LogData, nil
+	}
+	return parseTextLine(string(line))
+}
+
+// parseTextLine undoes TextFormatter:
+// "<timestamp> <id> (<turn>) <function> [<sev>] <msg>"
+func parseTextLine(line string) (LogData, error) {
+	var l LogData
+	if strings.HasPrefix(line, "#") {
+		var seq string
+		r, ok := cut(line[1:], " ", &seq)
+		n, err := strconv.ParseUint(seq, 10, 64)
+		if !ok || err != nil {
+			return LogData{}, errNotLogLine
+		}
+		l.Seq = n
+		line = r
+	}
+	rest, ok := cut(line, " ", &l.Timestamp)
+	if !ok {
+		return LogData{}, errNotLogLine
+	}
+	if rest, ok = cut(rest, " (", &l.ID); !ok {
+		return LogData{}, errNotLogLine
+	}
+	var turn string
+	if rest, ok = cut(rest, ") <", &turn); !ok {
+		return LogData{}, errNotLogLine
+	}
+	t, err := strconv.Atoi(turn)
+	if err != nil {
+		return LogData{}, errNotLogLine
+	}
+	l.Turn = t
+	if rest, ok = cut(rest, "> [", &l.Function); !ok {
+		return LogData{}, errNotLogLine
+	}
+	if rest, ok = cut(rest, "] ", &l.Sev); !ok {
+		return LogData{}, errNotLogLine
+	}
+	l.Msg = rest
+	l.Level = levelFromName(l.Sev)
+	if len(l.Sev) == 1 {
+		// written with ShortSeverity, give back the full name
+		l.Sev = levelName(l.Level)
+	}
+	return l, nil
+}
+
+// cut puts what is before sep in *before and returns what is after
+func cut(s, sep string, before *string) (string, bool) {
+	i := strings.Index(s, sep)
+	if i < 0 {
+		return s, false
+	}
+	*before = s[:i]
+	return s[i+len(sep):], true
+}
+
+// levelFromName is the reverse of levelName, unknown names are info
+// the one letter names from TextFormatter.ShortSeverity work too
+func levelFromName(name string) SnakeLoggerLevel {
+	if len(name) == 1 {
+		for l, v := range levelMap {
+			if shortSev(v) == name {
+				return l
+			}
+		}
+	}
+	l, _ := ParseLevel(name)
+	return l
+}
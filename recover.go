@@ -0,0 +1,35 @@
+package snakeLoggerFile
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// recoverPanics is on unless SetRecoverPanics(false) is called
+var recoverPanics int32 = 1
+
+// SetRecoverPanics decides what happens when a formatter, sink or observer
+// panics on the writer goroutine
+// on (the default) the entry is dropped, the panic is recorded in Health
+// and the writer carries on, off lets the panic crash the process,
+// which is handy when debugging a custom formatter
+func SetRecoverPanics(on bool) {
+	var v int32
+	if on {
+		v = 1
+	}
+	atomic.StoreInt32(&recoverPanics, v)
+}
+
+// safeDispatch is dispatch that survives a panic while handling m
+func safeDispatch(m LogData) (err error) {
+	if atomic.LoadInt32(&recoverPanics) == 1 {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("panic writing log entry %q: %v", m.Msg, r)
+				recordError(err)
+			}
+		}()
+	}
+	return dispatch(m)
+}
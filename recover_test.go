@@ -0,0 +1,44 @@
+package snakeLoggerFile
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// panicFormatter blows up on any entry whose message mentions boom
+type panicFormatter struct{}
+
+func (panicFormatter) Format(l LogData) []byte {
+	if strings.Contains(l.Msg, "boom") {
+		panic("formatter exploded")
+	}
+	return TextFormatter{}.Format(l)
+}
+
+func TestPanickingFormatterKeepsWriter(t *testing.T) {
+	fsys := NewMemFS()
+	SetFileSystem(fsys)
+	defer SetFileSystem(nil)
+	fs := newFileSink()
+	onlySink(t, fs)
+	before := Health().WriteErrors
+
+	l := NewLogger("debug")
+	l.UpdateName("panicky")
+	l.UpdateFormatter(panicFormatter{})
+	runWriter(l.newEntry(InfoLevel, "boom", time.Now(), nil),
+		l.newEntry(InfoLevel, "after the panic", time.Now(), nil))
+
+	b, _ := fsys.ReadFile(fs.dir() + "/panicky.log")
+	if !strings.Contains(string(b), "after the panic") {
+		t.Errorf("panicky.log = %q, want the entry after the panic", b)
+	}
+	if strings.Contains(string(b), "boom") {
+		t.Errorf("panicky.log = %q, the entry that panicked was written", b)
+	}
+	h := Health()
+	if h.WriteErrors <= before || !strings.Contains(h.LastError.Error(), "formatter exploded") {
+		t.Errorf("Health = %+v, want the panic recorded", h)
+	}
+}
@@ -0,0 +1,82 @@
+package snakeLoggerFile
+
+import (
+	"fmt"
+	"regexp"
+	"sync"
+)
+
+// redactedValue replaces anything that is redacted
+const redactedValue = "***"
+
+// Redaction lists what to keep out of the logs
+type Redaction struct {
+	// Keys are field names whose values are replaced
+	Keys []string
+	// Patterns are regular expressions, matches in the message are replaced
+	Patterns []string
+}
+
+// redactor is the compiled form of a Redaction
+type redactor struct {
+	keys     map[string]struct{}
+	patterns []*regexp.Regexp
+}
+
+var (
+	redactMu sync.RWMutex
+	redact   *redactor
+)
+
+// SetRedaction replaces sensitive values with *** before anything is written
+// it is applied on the writer, so every sink and observer gets the redacted entry,
+// and to entries the Stderr overflow policy writes itself
+// a pattern that doesn't compile is an error and leaves the old setting in place
+// bytes given to Raw are written as is and are not redacted
+// an empty Redaction turns it off
+func SetRedaction(r Redaction) error {
+	var rd *redactor
+	if len(r.Keys) > 0 || len(r.Patterns) > 0 {
+		rd = &redactor{keys: make(map[string]struct{}, len(r.Keys))}
+		for _, k := range r.Keys {
+			rd.keys[k] = struct{}{}
+		}
+		for _, p := range r.Patterns {
+			re, err := regexp.Compile(p)
+			if err != nil {
+				return fmt.Errorf("bad redaction pattern %q: %w", p, err)
+			}
+			rd.patterns = append(rd.patterns, re)
+		}
+	}
+	redactMu.Lock()
+	redact = rd
+	redactMu.Unlock()
+	return nil
+}
+
+// redactEntry returns m with redacted fields and message
+// the fields map is copied first, it may still belong to a logger
+func redactEntry(m LogData) LogData {
+	redactMu.RLock()
+	rd := redact
+	redactMu.RUnlock()
+	if rd == nil {
+		return m
+	}
+	for _, re := range rd.patterns {
+		m.Msg = re.ReplaceAllString(m.Msg, redactedValue)
+	}
+	if len(m.Fields) == 0 || len(rd.keys) == 0 {
+		return m
+	}
+	fields := make(map[string]interface{}, len(m.Fields))
+	for k, v := range m.Fields {
+		if _, ok := rd.keys[k]; ok {
+			v = redactedValue
+		}
+		fields[k] = v
+	}
+	m.Fields = fields
+	return m
+}
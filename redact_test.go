@@ -0,0 +1,124 @@
+package snakeLoggerFile
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRedactEntry(t *testing.T) {
+	tests := []struct {
+		name       string
+		redaction  Redaction
+		msg        string
+		fields     map[string]interface{}
+		wantMsg    string
+		wantFields map[string]interface{}
+	}{
+		{
+			name:       "key",
+			redaction:  Redaction{Keys: []string{"token"}},
+			msg:        "auth",
+			fields:     map[string]interface{}{"token": "s3cret", "user": "sam"},
+			wantMsg:    "auth",
+			wantFields: map[string]interface{}{"token": "***", "user": "sam"},
+		},
+		{
+			name:      "keys leave the message alone",
+			redaction: Redaction{Keys: []string{"token"}},
+			msg:       "token s3cret",
+			wantMsg:   "token s3cret",
+		},
+		{
+			name:       "pattern",
+			redaction:  Redaction{Patterns: []string{`Bearer \S+`}},
+			msg:        "header Bearer abc.def kept",
+			wantMsg:    "header *** kept",
+			fields:     map[string]interface{}{"auth": "Bearer abc.def"},
+			wantFields: map[string]interface{}{"auth": "Bearer abc.def"},
+		},
+		{
+			name:      "every pattern and every match",
+			redaction: Redaction{Patterns: []string{`\d{4}-\d{4}`, `pw=\w+`}},
+			msg:       "card 1234-5678 and 8765-4321 pw=hunter2",
+			wantMsg:   "card *** and *** ***",
+		},
+		{
+			name:       "off",
+			msg:        "token s3cret",
+			fields:     map[string]interface{}{"token": "s3cret"},
+			wantMsg:    "token s3cret",
+			wantFields: map[string]interface{}{"token": "s3cret"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := SetRedaction(tt.redaction); err != nil {
+				t.Fatal(err)
+			}
+			defer SetRedaction(Redaction{})
+			var orig map[string]interface{}
+			if tt.fields != nil {
+				orig = make(map[string]interface{}, len(tt.fields))
+				for k, v := range tt.fields {
+					orig[k] = v
+				}
+			}
+
+			got := redactEntry(LogData{Msg: tt.msg, Fields: tt.fields})
+			if got.Msg != tt.wantMsg {
+				t.Errorf("Msg = %q, want %q", got.Msg, tt.wantMsg)
+			}
+			if len(got.Fields) != len(tt.wantFields) {
+				t.Fatalf("Fields = %v, want %v", got.Fields, tt.wantFields)
+			}
+			for k, v := range tt.wantFields {
+				if got.Fields[k] != v {
+					t.Errorf("Fields[%q] = %v, want %v", k, got.Fields[k], v)
+				}
+			}
+			// the logger's own map must not change
+			for k, v := range orig {
+				if tt.fields[k] != v {
+					t.Errorf("the caller's Fields[%q] became %v", k, tt.fields[k])
+				}
+			}
+		})
+	}
+}
+
+func TestSetRedactionBadPatternKeepsOld(t *testing.T) {
+	if err := SetRedaction(Redaction{Keys: []string{"token"}}); err != nil {
+		t.Fatal(err)
+	}
+	defer SetRedaction(Redaction{})
+	if err := SetRedaction(Redaction{Patterns: []string{"("}}); err == nil {
+		t.Fatal("a pattern that doesn't compile was accepted")
+	}
+	got := redactEntry(LogData{Fields: map[string]interface{}{"token": "s3cret"}})
+	if got.Fields["token"] != redactedValue {
+		t.Errorf("token = %v, the old redaction was dropped", got.Fields["token"])
+	}
+}
+
+func TestRedactionReachesTheFile(t *testing.T) {
+	fsys := NewMemFS()
+	SetFileSystem(fsys)
+	defer SetFileSystem(nil)
+	fs := newFileSink()
+	onlySink(t, fs)
+	if err := SetRedaction(Redaction{Keys: []string{"token"}, Patterns: []string{`pw=\w+`}}); err != nil {
+		t.Fatal(err)
+	}
+	defer SetRedaction(Redaction{})
+
+	l := NewLogger("debug").ToSnake("secret")
+	runWriter(l.newEntry(InfoLevel, "login pw=hunter2", time.Now(), map[string]interface{}{"token": "s3cret"}))
+	b, _ := fsys.ReadFile(fs.dir() + "/secret.log")
+	if strings.Contains(string(b), "s3cret") || strings.Contains(string(b), "hunter2") {
+		t.Errorf("secret.log = %q, want the token and password redacted", b)
+	}
+	if !strings.Contains(string(b), "token=***") {
+		t.Errorf("secret.log = %q, want token=***", b)
+	}
+}
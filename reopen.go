@@ -0,0 +1,59 @@
+package snakeLoggerFile
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+var (
+	hupMu   sync.Mutex
+	hupChan chan os.Signal
+)
+
+// Reopen flushes and closes every cached file handle,
+// the next entry for each file opens it again by name
+// call it after something outside the process has renamed the log files
+func Reopen() error {
+	defaultFileSink.mu.Lock()
+	err := defaultFileSink.closeAll()
+	b := defaultFileSink.backup
+	defaultFileSink.mu.Unlock()
+	if b != nil {
+		b.files.mu.Lock()
+		if berr := b.files.closeAll(); berr != nil && err == nil {
+			err = fmt.Errorf("backup dir: %w", berr)
+		}
+		b.files.mu.Unlock()
+	}
+	return err
+}
+
+// SetReopenOnSIGHUP calls Reopen whenever the process gets a SIGHUP,
+// which is what logrotate sends once it has moved the files
+// it is off by default so the package doesn't take over the signal
+func SetReopenOnSIGHUP(on bool) {
+	hupMu.Lock()
+	defer hupMu.Unlock()
+	if hupChan != nil {
+		signal.Stop(hupChan)
+		close(hupChan)
+		hupChan = nil
+	}
+	if !on {
+		return
+	}
+	hupChan = make(chan os.Signal, 1)
+	signal.Notify(hupChan, syscall.SIGHUP)
+	go reopenOnSignal(hupChan)
+}
+
+func reopenOnSignal(c chan os.Signal) {
+	for range c {
+		if err := Reopen(); err != nil {
+			recordError(err)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package snakeLoggerFile
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// RetryPolicy controls how a RetrySink retries a failing sink
+type RetryPolicy struct {
+	// MaxAttempts is how many times to try one entry before spilling it
+	MaxAttempts int
+	// InitialBackoff is the wait after the first failure, it doubles each try
+	InitialBackoff time.Duration
+	// MaxBackoff caps the wait between tries
+	MaxBackoff time.Duration
+	// QueueSize is how many entries can wait for delivery
+	// once it is full new entries spill straight to file
+	QueueSize int
+}
+
+// DefaultRetryPolicy is used for any zero value in a RetryPolicy
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    5,
+	InitialBackoff: 100 * time.Millisecond,
+	MaxBackoff:     5 * time.Second,
+	QueueSize:      1024,
+}
+
+// RetrySink wraps a sink that talks over the network
+// entries are queued and delivered from its own goroutine, so a slow
+// or down endpoint never holds up the writer or the caller
+// entries that still fail after MaxAttempts are written to the log files
+// instead, unless the files get them anyway at their level
+type RetrySink struct {
+	// counters first so they stay 64 bit aligned for atomic
+	retries uint64
+	spills  uint64
+
+	next   Sink
+	policy RetryPolicy
+	queue  chan LogData
+	done   chan struct{}
+	// mu guards closed, Write holds it for reading while it queues
+	// so Close can't close the queue under it
+	mu     sync.RWMutex
+	closed bool
+}
+
+// NewRetrySink starts delivering to next using policy
+func NewRetrySink(next Sink, policy RetryPolicy) *RetrySink {
+	if policy.MaxAttempts <= 0 {
+		policy.MaxAttempts = DefaultRetryPolicy.MaxAttempts
+	}
+	if policy.InitialBackoff <= 0 {
+		policy.InitialBackoff = DefaultRetryPolicy.InitialBackoff
+	}
+	if policy.MaxBackoff <= 0 {
+		policy.MaxBackoff = DefaultRetryPolicy.MaxBackoff
+	}
+	if policy.QueueSize <= 0 {
+		policy.QueueSize = DefaultRetryPolicy.QueueSize
+	}
+	r := &RetrySink{
+		next:   next,
+		policy: policy,
+		queue:  make(chan LogData, policy.QueueSize),
+		done:   make(chan struct{}),
+	}
+	go r.run()
+	return r
+}
+
+// Write implements Sink, it only queues the entry
+// once the sink is closed entries spill straight to file
+func (r *RetrySink) Write(l LogData) error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.closed {
+		return r.spill(l, fmt.Errorf("retry sink closed"))
+	}
+	select {
+	case r.queue <- l:
+		return nil
+	default:
+		return r.spill(l, fmt.Errorf("retry queue full"))
+	}
+}
+
+// Retries is how many extra delivery attempts have been made
+func (r *RetrySink) Retries() uint64 {
+	return atomic.LoadUint64(&r.retries)
+}
+
+// Spills is how many entries went to file instead of the wrapped sink
+func (r *RetrySink) Spills() uint64 {
+	return atomic.LoadUint64(&r.spills)
+}
+
+// Close stops taking entries and waits for the queue to be delivered
+func (r *RetrySink) Close() error {
+	r.mu.Lock()
+	if !r.closed {
+		r.closed = true
+		close(r.queue)
+	}
+	r.mu.Unlock()
+	<-r.done
+	return nil
+}
+
+func (r *RetrySink) run() {
+	defer close(r.done)
+	for l := range r.queue {
+		r.deliver(l)
+	}
+}
+
+func (r *RetrySink) deliver(l LogData) {
+	wait := r.policy.InitialBackoff
+	var err error
+	for attempt := 1; attempt <= r.policy.MaxAttempts; attempt++ {
+		if attempt > 1 {
+			atomic.AddUint64(&r.retries, 1)
+			time.Sleep(wait)
+			wait *= 2
+			if wait > r.policy.MaxBackoff {
+				wait = r.policy.MaxBackoff
+			}
+		}
+		if err = r.next.Write(l); err == nil {
+			return
+		}
+	}
+	r.spill(l, err)
+}
+
+// spill falls back to the regular log files so the entry isn't lost
+// when dispatch already gave it to them it is not written a second time
+func (r *RetrySink) spill(l LogData, cause error) error {
+	atomic.AddUint64(&r.spills, 1)
+	recordError(fmt.Errorf("sink failed, spilling to file: %w", cause))
+	if fileSinkGets(l.Level) {
+		return nil
+	}
+	return defaultFileSink.Write(l)
+}
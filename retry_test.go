@@ -0,0 +1,27 @@
+package snakeLoggerFile
+
+import (
+	"io/ioutil"
+	"testing"
+)
+
+func TestRetrySinkWriteAfterClose(t *testing.T) {
+	fsys := useMemFS(t)
+	// the files skip info, so the spill is the entry's only way to disk
+	SetFileLevel(WarnLevel)
+	defer SetFileLevel(DebugLevel)
+
+	r := NewRetrySink(NewWriterSink(ioutil.Discard), RetryPolicy{})
+	r.Close()
+	if err := r.Write(LogData{SnakeName: "late", Level: InfoLevel, Msg: "after close"}); err != nil {
+		t.Fatalf("Write after Close: %v", err)
+	}
+	r.Close()
+
+	if got := r.Spills(); got != 1 {
+		t.Errorf("Spills() = %d, want 1", got)
+	}
+	if lines := readLog(t, fsys, "late.log"); countContaining(lines, "after close") != 1 {
+		t.Errorf("late.log = %q, want the entry spilled", lines)
+	}
+}
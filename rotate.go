@@ -0,0 +1,421 @@
+package snakeLoggerFile
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RotationConfig controls when the per snake files are rolled over
+// the zero value is no rotation, which is the default
+type RotationConfig struct {
+	// MaxSize rolls a file over before a write would take it past this many bytes
+	// 0 means files are never rotated for size
+	MaxSize int64
+	// Compress gzips rotated files in the background
+	Compress bool
+	// CompressAfter leaves rotated files plain until they are at least this old
+	// so recent ones can still be grepped
+	CompressAfter time.Duration
+	// SweepInterval is how often the background sweeper runs, default one minute
+	SweepInterval time.Duration
+	// Symlink writes into timestamped files from the start and keeps
+	// <snake>.log as a symlink to the current one, for tailing through rotations
+	Symlink bool
+	// Daily also rolls a file over when an entry's date (the first part of
+	// its timestamp) is not the date of the last entry written to the file
+	Daily bool
+	// Keep is how many rotated files to hold on to for each snake, the
+	// oldest are removed after each roll. 0 keeps them all
+	Keep int
+	// MaxAge rolls a file over once it has been written to for this long,
+	// counted from its first write this run, or its last change for a file
+	// from before. 0 means files are never rotated for age
+	MaxAge time.Duration
+}
+
+const (
+	defaultSweepInterval = time.Minute
+	// rotatedTimeFormat is added to the file name on rotation: mysnake-2024-05-01T12-30-00.log
+	rotatedTimeFormat = "2006-01-02T15-04-05"
+)
+
+// rotatedName matches files made by rotate, with or without a .N to keep them unique
+var rotatedName = regexp.MustCompile(`-\d{4}-\d{2}-\d{2}T\d{2}-\d{2}-\d{2}(\.\d+)?\.log$`)
+
+// rotatedSuffix is what rotate adds after the base name, a rotated file
+// may also have been compressed since
+var rotatedSuffix = regexp.MustCompile(`^-(\d{4}-\d{2}-\d{2}T\d{2}-\d{2}-\d{2})(?:\.(\d+))?\.log(?:\.gz)?$`)
+
+// SetMaxFileSize rolls the log files over before they grow past bytes,
+// leaving the rest of the rotation settings alone. 0 turns it off
+func SetMaxFileSize(bytes int64) {
+	defaultFileSink.mu.Lock()
+	defaultFileSink.rotation.MaxSize = bytes
+	defaultFileSink.mu.Unlock()
+}
+
+// SetRotation changes the rotation settings for the log files
+// and starts or stops the compression sweeper to match
+func SetRotation(cfg RotationConfig) {
+	defaultFileSink.setRotation(cfg)
+}
+
+// SetSinkRotation is SetRotation for a sink made with NewFileSink,
+// each file sink rotates and sweeps its own directory
+// it is an error if s doesn't write files
+func SetSinkRotation(s Sink, cfg RotationConfig) error {
+	fs, ok := s.(*fileSink)
+	if !ok {
+		return fmt.Errorf("sink %T does not write log files", s)
+	}
+	fs.setRotation(cfg)
+	return nil
+}
+
+// setRotation is SetRotation for fs
+func (fs *fileSink) setRotation(cfg RotationConfig) {
+	if cfg.SweepInterval <= 0 {
+		cfg.SweepInterval = defaultSweepInterval
+	}
+	fs.mu.Lock()
+	fs.rotation = cfg
+	fs.mu.Unlock()
+	fs.restartSweeper(cfg.Compress, cfg)
+}
+
+// restartSweeper stops the compression sweeper of fs, then starts
+// a new one for cfg if on
+func (fs *fileSink) restartSweeper(on bool, cfg RotationConfig) {
+	fs.sweepMu.Lock()
+	defer fs.sweepMu.Unlock()
+	if fs.sweepStop != nil {
+		close(fs.sweepStop)
+		fs.sweepStop = nil
+	}
+	if on {
+		fs.sweepStop = make(chan struct{})
+		go sweep(fs, cfg, fs.sweepStop)
+	}
+}
+
+// needsRotation says if writing n more bytes to filename goes past the limit,
+// with Daily if day is not the day filename was last written on,
+// or with MaxAge if filename is too old
+// an empty file is never rotated, so one huge line still gets written
+func (fs *fileSink) needsRotation(fsys FileSystem, filename string, n int, day string) bool {
+	size, ok := fs.fileSize(fsys, filename)
+	if !ok || size == 0 {
+		return false
+	}
+	if fs.rotation.Daily && day != "" && fs.lastDay(fsys, filename) != day {
+		return true
+	}
+	if fs.rotation.MaxAge > 0 && time.Since(fs.startedAt(fsys, filename)) >= fs.rotation.MaxAge {
+		return true
+	}
+	return fs.rotation.MaxSize > 0 && size+int64(n) > fs.rotation.MaxSize
+}
+
+// entryDay is the date part of an entry's timestamp, used for Daily
+func entryDay(m LogData) string {
+	if len(m.Timestamp) < 10 {
+		return ""
+	}
+	return m.Timestamp[:10]
+}
+
+// lastDay is the day filename was last written on, for a file from
+// before this run that is the day it was last modified
+func (fs *fileSink) lastDay(fsys FileSystem, filename string) string {
+	if day, ok := fs.days[filename]; ok {
+		return day
+	}
+	info, err := fsys.Stat(filename)
+	if err != nil {
+		return ""
+	}
+	day := formatTimestamp(info.ModTime())[:10]
+	fs.days[filename] = day
+	return day
+}
+
+// startedAt is when filename was first written to this run, for a file
+// from before this run it is when the file was last changed
+func (fs *fileSink) startedAt(fsys FileSystem, filename string) time.Time {
+	if t, ok := fs.started[filename]; ok {
+		return t
+	}
+	t := time.Now()
+	if info, err := fsys.Stat(filename); err == nil {
+		t = info.ModTime()
+	}
+	fs.started[filename] = t
+	return t
+}
+
+// rotated forgets what was known about filename, it is a new file now
+func (fs *fileSink) rotated(filename string) {
+	delete(fs.days, filename)
+	delete(fs.started, filename)
+}
+
+// prune removes the oldest rotated files of filename past RotationConfig.Keep
+// skip is left alone and not counted, it is the segment being written to
+func (fs *fileSink) prune(fsys FileSystem, filename, skip string) {
+	if fs.rotation.Keep <= 0 {
+		return
+	}
+	dir, base := path.Split(strings.TrimSuffix(filename, ".log"))
+	infos, err := fsys.ReadDir(path.Clean(dir))
+	if err != nil {
+		recordError(err)
+		return
+	}
+	type rotated struct {
+		name string
+		ts   string
+		n    int
+	}
+	var old []rotated
+	for _, info := range infos {
+		name := info.Name()
+		if info.IsDir() || !strings.HasPrefix(name, base) || dir+name == skip {
+			continue
+		}
+		sub := rotatedSuffix.FindStringSubmatch(name[len(base):])
+		if sub == nil {
+			continue
+		}
+		n, _ := strconv.Atoi(sub[2])
+		old = append(old, rotated{dir + name, sub[1], n})
+	}
+	if len(old) <= fs.rotation.Keep {
+		return
+	}
+	// newest first, a .N file was made after the one without
+	sort.Slice(old, func(i, j int) bool {
+		if old[i].ts != old[j].ts {
+			return old[i].ts > old[j].ts
+		}
+		return old[i].n > old[j].n
+	})
+	for _, r := range old[fs.rotation.Keep:] {
+		if err := fsys.Remove(r.name); err != nil {
+			recordError(err)
+		}
+	}
+}
+
+// rotate renames filename out of the way with a timestamp,
+// the next open then starts a fresh file
+func rotate(fsys FileSystem, filename string, t time.Time) error {
+	return fsys.Rename(filename, rotatedFilename(fsys, filename, t))
+}
+
+// rotateFile is rotate for a file of fs, pruning old ones after
+func (fs *fileSink) rotateFile(fsys FileSystem, filename string, t time.Time) error {
+	if err := rotate(fsys, filename, t); err != nil {
+		return err
+	}
+	fs.rotated(filename)
+	fs.prune(fsys, filename, "")
+	return nil
+}
+
+// rotatedFilename picks an unused timestamped name for filename
+func rotatedFilename(fsys FileSystem, filename string, t time.Time) string {
+	base := strings.TrimSuffix(filename, ".log")
+	target := fmt.Sprintf("%s-%s.log", base, t.Format(rotatedTimeFormat))
+	for i := 1; ; i++ {
+		// a compressed one counts too, compressing it again would overwrite it
+		if !exists(fsys, target) && !exists(fsys, target+".gz") {
+			return target
+		}
+		target = fmt.Sprintf("%s-%s.%d.log", base, t.Format(rotatedTimeFormat), i)
+	}
+}
+
+// exists says if name is there, a dangling symlink included
+func exists(fsys FileSystem, name string) bool {
+	_, err := fsys.Lstat(name)
+	return !os.IsNotExist(err)
+}
+
+// segmentFor is used with RotationConfig.Symlink, it returns the file
+// link should currently be written to, starting a new one when needed
+// link is then pointed at it, so tailers of link follow each roll
+func (fs *fileSink) segmentFor(fsys FileSystem, link string, n int, day string) (string, error) {
+	target, ok := fs.segments[link]
+	if ok && !fs.needsRotation(fsys, target, n, day) {
+		return target, nil
+	}
+	now := time.Now()
+	if !ok {
+		// a plain file from before symlinks were turned on would be replaced by the link
+		if info, err := fsys.Lstat(link); err == nil && info.Mode()&os.ModeSymlink == 0 {
+			if err := rotate(fsys, link, now); err != nil {
+				return "", err
+			}
+		}
+	}
+	return fs.newSegment(fsys, link, now)
+}
+
+// newSegment starts a new file for link and points link at it
+// the segment it replaces is closed, nothing else will write to it
+func (fs *fileSink) newSegment(fsys FileSystem, link string, now time.Time) (string, error) {
+	if old, ok := fs.segments[link]; ok {
+		if err := fs.closeHandle(old); err != nil {
+			recordError(err)
+		}
+	}
+	target := rotatedFilename(fsys, link, now)
+	if err := pointLink(fsys, link, target); err != nil {
+		return "", err
+	}
+	fs.segments[link] = target
+	fs.prune(fsys, link, target)
+	return target, nil
+}
+
+// Rotate rolls over the log file for snakeName right now, whatever its size
+// what was buffered is written to the old file first
+// it is an error if the snake has no log file yet
+func Rotate(snakeName string) error {
+	fs := defaultFileSink
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	fsys, gen := fileSystemAndGen()
+	fs.prepareDir(fsys, gen)
+	return fs.rotateNow(fsys, fs.filename(snakeName))
+}
+
+// RotateAll rolls over every log file written this session
+// it carries on past a failure and returns the first error
+func RotateAll() error {
+	fs := defaultFileSink
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	fsys, gen := fileSystemAndGen()
+	fs.prepareDir(fsys, gen)
+	var first error
+	for name := range fs.written {
+		if err := fs.rotateNow(fsys, name); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// rotateNow rolls filename over, fs.mu must be held
+// holding the lock means no write can land half in the old file
+func (fs *fileSink) rotateNow(fsys FileSystem, filename string) error {
+	now := time.Now()
+	if fs.rotation.Symlink {
+		if _, ok := fs.segments[filename]; !ok {
+			return fmt.Errorf("no active log file %s to rotate", filename)
+		}
+		_, err := fs.newSegment(fsys, filename, now)
+		return err
+	}
+	if _, ok := fs.fileSize(fsys, filename); !ok {
+		return fmt.Errorf("no active log file %s to rotate", filename)
+	}
+	if err := fs.closeHandle(filename); err != nil {
+		return err
+	}
+	return fs.rotateFile(fsys, filename, now)
+}
+
+// pointLink atomically replaces link with a symlink to target
+func pointLink(fsys FileSystem, link, target string) error {
+	tmp := link + ".tmp"
+	fsys.Remove(tmp)
+	if err := fsys.Symlink(path.Base(target), tmp); err != nil {
+		return err
+	}
+	return fsys.Rename(tmp, link)
+}
+
+// sweep compresses old rotated files every interval until stop is closed
+func sweep(fs *fileSink, cfg RotationConfig, stop chan struct{}) {
+	t := time.NewTicker(cfg.SweepInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-t.C:
+			if dir := fs.dir(); dir != "" {
+				compressOld(currentFileSystem(), dir, cfg.CompressAfter, fs.activeSegments())
+			}
+		}
+	}
+}
+
+// compressOld gzips every rotated file in dir older than age
+// files in skip are still being written to and are left alone
+func compressOld(fsys FileSystem, dir string, age time.Duration, skip map[string]struct{}) {
+	infos, err := fsys.ReadDir(dir)
+	if err != nil {
+		recordError(err)
+		return
+	}
+	for _, info := range infos {
+		if info.IsDir() || !rotatedName.MatchString(info.Name()) {
+			continue
+		}
+		name := path.Join(dir, info.Name())
+		if _, ok := skip[name]; ok || time.Since(info.ModTime()) < age {
+			continue
+		}
+		if err := compressFile(fsys, name); err != nil {
+			recordError(err)
+		}
+	}
+}
+
+// activeSegments is the set of files the symlinks point at
+func (fs *fileSink) activeSegments() map[string]struct{} {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	res := make(map[string]struct{}, len(fs.segments))
+	for _, target := range fs.segments {
+		res[target] = struct{}{}
+	}
+	return res
+}
+
+// compressFile writes name.gz and removes name once that worked
+func compressFile(fsys FileSystem, name string) error {
+	in, err := fsys.Open(name)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := fsys.OpenFile(name+".gz", os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	zw := gzip.NewWriter(out)
+	_, err = io.Copy(zw, in)
+	if cerr := zw.Close(); err == nil {
+		err = cerr
+	}
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		fsys.Remove(name + ".gz")
+		return err
+	}
+	return fsys.Remove(name)
+}
@@ -0,0 +1,19 @@
+package snakeLoggerFile
+
+import "testing"
+
+func TestCompressOldSkipsActiveWithTrailingSlash(t *testing.T) {
+	fsys := NewMemFS()
+	active := "/x/snake-2026-01-02T03-04-05.log"
+	old := "/x/snake-2026-01-01T03-04-05.log"
+	memWrite(t, fsys, active, "current segment\n")
+	memWrite(t, fsys, old, "done\n")
+
+	compressOld(fsys, "/x/", 0, map[string]struct{}{active: {}})
+	if _, err := fsys.Stat(active); err != nil {
+		t.Errorf("the active segment was compressed: %v", err)
+	}
+	if _, err := fsys.Stat(old + ".gz"); err != nil {
+		t.Errorf("the old segment wasn't compressed: %v", err)
+	}
+}
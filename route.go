@@ -0,0 +1,122 @@
+package snakeLoggerFile
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RouteByField returns a filename builder for SetFilenameBuilder that
+// names each file after one value of the entry instead of the snake name
+// key is a JSON key of LogData (snake_name, id, function, sev, instance)
+// or the name of a field, entries without a value go to generic.log
+// the value is cleaned up so it is always a single safe file name
+func RouteByField(key string) func(LogData) string {
+	return func(l LogData) string {
+		v := routeValue(l, key)
+		if v == "" {
+			return "generic.log"
+		}
+		return safeFilename(v) + ".log"
+	}
+}
+
+// SetRouteField splits the log files by key, see RouteByField
+// "" or "snake_name" is the usual one file per snake
+func SetRouteField(key string) {
+	if key == "" || key == "snake_name" {
+		SetFilenameBuilder(nil)
+		return
+	}
+	defaultFileSink.setNames(RouteByField(key), key, PerSnake)
+}
+
+func routeValue(l LogData, key string) string {
+	switch key {
+	case "snake_name":
+		return l.SnakeName
+	case "id":
+		return l.ID
+	case "function":
+		return l.Function
+	case "sev":
+		return l.Sev
+	case "instance":
+		return l.Instance
+	}
+	v, ok := l.Fields[key]
+	if !ok || v == nil {
+		return ""
+	}
+	return fmt.Sprint(v)
+}
+
+// safeFilename keeps letters, digits, '-', '_' and '.', anything else
+// (path separators included) becomes '_', and a leading '.' is dropped
+// so the name can't climb out of the log directory or be hidden
+func safeFilename(s string) string {
+	s = strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			return r
+		}
+		return '_'
+	}, s)
+	s = strings.TrimLeft(s, ".")
+	if s == "" {
+		return "_"
+	}
+	return s
+}
+
+// FileLayout is how the log files are split up, see SetFileLayout
+type FileLayout int
+
+const (
+	// PerSnake is one file per snake, <snake>.log, the default
+	PerSnake FileLayout = iota
+	// PerGame is one file per game id, game-<id>.log, whichever snake logged
+	PerGame
+	// PerSnakePerGame is a directory per snake with a file per game in it,
+	// <snake>/game-<id>.log
+	PerSnakePerGame
+)
+
+// Filename is the filename builder for the layout, for SetFilenameBuilder
+// entries without a game id (see UpdateID and ForGame) go where PerSnake
+// would put them
+func (f FileLayout) Filename(l LogData) string {
+	if l.ID == "" || f == PerSnake {
+		return DefaultFilename(l)
+	}
+	game := "game-" + safeFilename(l.ID) + ".log"
+	if f == PerSnakePerGame && l.SnakeName != "" {
+		return l.SnakeName + "/" + game
+	}
+	return game
+}
+
+// SetFileLayout picks how the log files are split, it is shorthand for
+// SetFilenameBuilder(layout.Filename), with the same caveats
+func SetFileLayout(layout FileLayout) {
+	setFileLayout(defaultFileSink, layout)
+}
+
+// setFileLayout is SetFileLayout for any file sink
+func setFileLayout(fs *fileSink, layout FileLayout) {
+	if layout == PerSnake {
+		fs.setNames(nil, "", PerSnake)
+		return
+	}
+	fs.setNames(layout.Filename, "", layout)
+}
+
+// SetSinkFileLayout is SetFileLayout for a sink made with NewFileSink
+// it is an error if s doesn't write files
+func SetSinkFileLayout(s Sink, layout FileLayout) error {
+	fs, ok := s.(*fileSink)
+	if !ok {
+		return fmt.Errorf("sink %T does not write log files", s)
+	}
+	setFileLayout(fs, layout)
+	return nil
+}
@@ -0,0 +1,221 @@
+package snakeLoggerFile
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// limitSet is the SetSampling and SetRateLimit settings of a logger
+// it is replaced, never changed, so children can share it
+type limitSet map[SnakeLoggerLevel]*levelLimit
+
+// limitsMu serialises SetSampling and SetRateLimit, logging only loads the set
+var limitsMu sync.Mutex
+
+// suppressedNoticeInterval is how often the "suppressed" notices go out
+const suppressedNoticeInterval = time.Second
+
+// SuppressedKey is the field on a notice holding how many entries were dropped
+const SuppressedKey = "suppressed"
+
+var (
+	noticesMu sync.Mutex
+	// notices are the limits with dropped entries not yet given notice of
+	notices      = map[*levelLimit]struct{}{}
+	noticesStart sync.Once
+)
+
+// levelLimit is the sampling and rate limit for one level of a logger
+type levelLimit struct {
+	// owner is the logger SetSampling or SetRateLimit was called on,
+	// notices are written through it
+	owner *SnakeLogger
+	level SnakeLoggerLevel
+
+	mu sync.Mutex
+	// every keeps one entry out of every, 0 or 1 keeps them all
+	every uint64
+	seen  uint64
+	// rate is tokens per second, 0 means no rate limit
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+	// suppressed is dropped since the last notice
+	suppressed uint64
+}
+
+// allow says if an entry at t can go through
+func (ll *levelLimit) allow(t time.Time) bool {
+	ll.mu.Lock()
+	defer ll.mu.Unlock()
+	ok := true
+	if ll.every > 1 {
+		ll.seen++
+		ok = (ll.seen-1)%ll.every == 0
+	}
+	if ok && ll.rate > 0 {
+		if !ll.last.IsZero() {
+			ll.tokens += t.Sub(ll.last).Seconds() * ll.rate
+			if ll.tokens > ll.burst {
+				ll.tokens = ll.burst
+			}
+		}
+		ll.last = t
+		if ll.tokens < 1 {
+			ok = false
+		} else {
+			ll.tokens--
+		}
+	}
+	if !ok {
+		if ll.suppressed == 0 {
+			owesNotice(ll)
+		}
+		ll.suppressed++
+	}
+	return ok
+}
+
+// owesNotice puts ll in the set for the next notice, starting the ticker
+// the first time
+func owesNotice(ll *levelLimit) {
+	noticesMu.Lock()
+	notices[ll] = struct{}{}
+	noticesMu.Unlock()
+	noticesStart.Do(func() { go noticeLoop() })
+}
+
+// noticeLoop writes the notices every suppressedNoticeInterval until the writer stops
+func noticeLoop() {
+	t := time.NewTicker(suppressedNoticeInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+			flushNotices()
+		case <-writerDone:
+			return
+		}
+	}
+}
+
+// flushNotices writes a notice for every limit that dropped entries since the last one
+// Flush and Close call it so the count from the end of a burst isn't lost
+func flushNotices() {
+	noticesMu.Lock()
+	owed := notices
+	notices = map[*levelLimit]struct{}{}
+	noticesMu.Unlock()
+	for ll := range owed {
+		ll.mu.Lock()
+		n := ll.suppressed
+		ll.suppressed = 0
+		ll.mu.Unlock()
+		if n > 0 {
+			ll.owner.notice(ll.level, n)
+		}
+	}
+}
+
+// notice writes that n entries at level were dropped, it skips sampling
+// so the notice itself can't be dropped
+func (s *SnakeLogger) notice(level SnakeLoggerLevel, n uint64) {
+	if s.rec == nil {
+		gen, ok := beginSend()
+		if !ok {
+			return
+		}
+		defer endSend(gen)
+	}
+	msg := fmt.Sprintf("suppressed %d %s messages", n, levelName(level))
+	if m, ok := s.buildLog(level, msg, time.Now(), map[string]interface{}{SuppressedKey: n}); ok {
+		s.deliver(m)
+	}
+}
+
+// SetSampling keeps only one in every n entries at level from this logger,
+// the first, then the n+1th and so on. n of 1 or less keeps everything
+// once a second, and on Flush and Close, a notice says how many were dropped
+// entries are dropped before they are queued for the writer
+// children made after the call share the count with the logger
+func (s *SnakeLogger) SetSampling(level SnakeLoggerLevel, n int) {
+	limitsMu.Lock()
+	defer limitsMu.Unlock()
+	ll := s.limitFor(level)
+	if n < 1 {
+		n = 1
+	}
+	ll.every = uint64(n)
+	s.setLimit(level, ll)
+}
+
+// SetRateLimit lets at most perSecond entries at level through from this
+// logger, with bursts of up to burst, a token bucket. perSecond of 0 or
+// less turns it off. Like SetSampling a notice says how many were dropped
+// and children made after the call share the bucket
+func (s *SnakeLogger) SetRateLimit(level SnakeLoggerLevel, perSecond float64, burst int) {
+	limitsMu.Lock()
+	defer limitsMu.Unlock()
+	ll := s.limitFor(level)
+	if burst < 1 {
+		burst = 1
+	}
+	ll.rate = perSecond
+	ll.burst = float64(burst)
+	ll.tokens = float64(burst)
+	ll.last = time.Time{}
+	if perSecond <= 0 {
+		ll.rate = 0
+	}
+	s.setLimit(level, ll)
+}
+
+// loadLimits is the logger's current limitSet, nil when there is none
+func (s *SnakeLogger) loadLimits() limitSet {
+	ls, _ := s.limits.Load().(limitSet)
+	return ls
+}
+
+// limitFor is a fresh copy of the settings for level, to change and set
+// limitsMu must be held
+func (s *SnakeLogger) limitFor(level SnakeLoggerLevel) *levelLimit {
+	ll := &levelLimit{owner: s, level: level}
+	if old := s.loadLimits()[level]; old != nil {
+		old.mu.Lock()
+		ll.every, ll.rate, ll.burst, ll.tokens = old.every, old.rate, old.burst, old.tokens
+		old.mu.Unlock()
+	}
+	return ll
+}
+
+// setLimit puts ll in place for level, the set is copied since
+// children made earlier and log calls running now share the old one
+// limitsMu must be held
+func (s *SnakeLogger) setLimit(level SnakeLoggerLevel, ll *levelLimit) {
+	old := s.loadLimits()
+	m := make(limitSet, len(old)+1)
+	for k, v := range old {
+		m[k] = v
+	}
+	if ll.every <= 1 && ll.rate == 0 {
+		delete(m, level)
+	} else {
+		m[level] = ll
+	}
+	if len(m) == 0 {
+		m = nil
+	}
+	s.limits.Store(m)
+}
+
+// sampled applies SetSampling and SetRateLimit to an entry at level,
+// false drops the entry
+func (s *SnakeLogger) sampled(level SnakeLoggerLevel, t time.Time) bool {
+	ll := s.loadLimits()[level]
+	if ll == nil {
+		return true
+	}
+	return ll.allow(t)
+}
@@ -0,0 +1,55 @@
+package snakeLoggerFile
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestSamplingNoticeAfterBurst(t *testing.T) {
+	l := NewTestLogger()
+	l.SetSampling(DebugLevel, 10)
+	for i := 0; i < 25; i++ {
+		l.Debug("tick")
+	}
+	if got := len(l.Entries()); got != 3 {
+		t.Fatalf("kept %d entries, want 3", got)
+	}
+
+	// the burst is over, the count must still come out
+	flushNotices()
+	last, ok := l.LastEntry()
+	if !ok || last.Fields[SuppressedKey] != uint64(22) {
+		t.Fatalf("last entry %+v, want a notice of 22 suppressed", last)
+	}
+
+	// nothing was dropped since, so no second notice
+	flushNotices()
+	if got := len(l.Entries()); got != 4 {
+		t.Fatalf("%d entries after a quiet flush, want 4", got)
+	}
+}
+
+func TestSetSamplingWhileLogging(t *testing.T) {
+	l := NewTestLogger()
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				l.Debug("busy")
+			}
+		}
+	}()
+	for i := 1; i <= 100; i++ {
+		l.SetSampling(DebugLevel, i%5)
+		l.SetRateLimit(InfoLevel, float64(i), i)
+	}
+	close(stop)
+	wg.Wait()
+	flushNotices()
+}
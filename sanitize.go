@@ -0,0 +1,125 @@
+package snakeLoggerFile
+
+import (
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"unicode/utf8"
+)
+
+// SanitizeMode is what happens to control characters in messages
+type SanitizeMode int32
+
+const (
+	// SanitizeOff leaves messages alone, the default since the check costs a scan
+	SanitizeOff SanitizeMode = iota
+	// SanitizeStrip removes control characters, and ANSI escape sequences whole
+	SanitizeStrip
+	// SanitizeEscape writes control characters as \xNN so they can still be seen
+	SanitizeEscape
+)
+
+var sanitizeMode int32
+
+// SetSanitize picks how control characters (NUL, ESC, DEL and the like)
+// in messages are handled before they reach the files and terminals
+// tabs and newlines are kept, see TextFormatter.EscapeNewlines for those
+func SetSanitize(mode SanitizeMode) {
+	atomic.StoreInt32(&sanitizeMode, int32(mode))
+}
+
+// isControl is true for the bytes the sanitizer deals with
+func isControl(c byte) bool {
+	return (c < 0x20 && c != '\t' && c != '\n') || c == 0x7f
+}
+
+// sanitize applies the current SanitizeMode to msg
+func sanitize(msg string) string {
+	mode := SanitizeMode(atomic.LoadInt32(&sanitizeMode))
+	if mode == SanitizeOff {
+		return msg
+	}
+	i := 0
+	for i < len(msg) && !isControl(msg[i]) {
+		i++
+	}
+	if i == len(msg) {
+		return msg
+	}
+	var b strings.Builder
+	b.Grow(len(msg))
+	b.WriteString(msg[:i])
+	for ; i < len(msg); i++ {
+		c := msg[i]
+		if !isControl(c) {
+			b.WriteByte(c)
+			continue
+		}
+		if mode == SanitizeEscape {
+			b.WriteString(`\x`)
+			if c < 0x10 {
+				b.WriteByte('0')
+			}
+			b.WriteString(strconv.FormatUint(uint64(c), 16))
+			continue
+		}
+		if c == 0x1b && i+1 < len(msg) && msg[i+1] == '[' {
+			// skip a CSI sequence like ESC[31m up to its final byte
+			i += 2
+			for i < len(msg) && (msg[i] < 0x40 || msg[i] > 0x7e) {
+				i++
+			}
+		}
+	}
+	return b.String()
+}
+
+// UTF8Mode is what happens to invalid UTF-8 in messages and snake names
+type UTF8Mode int32
+
+const (
+	// UTF8Keep writes the bytes as they are, the default
+	// JSON output still comes out valid, the encoder replaces them
+	UTF8Keep UTF8Mode = iota
+	// UTF8Replace swaps each invalid byte for the replacement rune U+FFFD
+	UTF8Replace
+	// UTF8Escape writes each invalid byte as \xNN
+	UTF8Escape
+)
+
+var utf8Mode int32
+
+// SetInvalidUTF8 picks how invalid UTF-8 in Msg and SnakeName is handled,
+// so text viewers and JSON readers agree on what was logged
+func SetInvalidUTF8(mode UTF8Mode) {
+	atomic.StoreInt32(&utf8Mode, int32(mode))
+}
+
+// fixUTF8 applies the current UTF8Mode to s
+func fixUTF8(s string) string {
+	mode := UTF8Mode(atomic.LoadInt32(&utf8Mode))
+	if mode == UTF8Keep || utf8.ValidString(s) {
+		return s
+	}
+	var b strings.Builder
+	b.Grow(len(s) + 8)
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r != utf8.RuneError || size != 1 {
+			b.WriteString(s[i : i+size])
+			i += size
+			continue
+		}
+		if mode == UTF8Replace {
+			b.WriteRune(utf8.RuneError)
+		} else {
+			b.WriteString(`\x`)
+			if s[i] < 0x10 {
+				b.WriteByte('0')
+			}
+			b.WriteString(strconv.FormatUint(uint64(s[i]), 16))
+		}
+		i++
+	}
+	return b.String()
+}
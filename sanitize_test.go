@@ -0,0 +1,92 @@
+package snakeLoggerFile
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSanitize(t *testing.T) {
+	tests := []struct {
+		name string
+		mode SanitizeMode
+		msg  string
+		want string
+	}{
+		{"off", SanitizeOff, "a\x1b[31mred\x00", "a\x1b[31mred\x00"},
+		{"clean message", SanitizeStrip, "plain\ttext\n", "plain\ttext\n"},
+		{"strip ansi colour", SanitizeStrip, "a\x1b[31mred\x1b[0m b", "ared b"},
+		{"strip nul and del", SanitizeStrip, "x\x00y\x7fz", "xyz"},
+		{"strip lone escape", SanitizeStrip, "x\x1by", "xy"},
+		{"strip keeps tab and newline", SanitizeStrip, "a\t\x07b\n", "a\tb\n"},
+		{"escape ansi", SanitizeEscape, "a\x1b[31m", `a\x1b[31m`},
+		{"escape nul and del", SanitizeEscape, "x\x00y\x7f", `x\x00y\x7f`},
+		{"escape keeps tab and newline", SanitizeEscape, "a\tb\n\r", "a\tb\n" + `\x0d`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetSanitize(tt.mode)
+			defer SetSanitize(SanitizeOff)
+			if got := sanitize(tt.msg); got != tt.want {
+				t.Errorf("sanitize(%q) = %q, want %q", tt.msg, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSanitizeReachesTheFile(t *testing.T) {
+	fsys := NewMemFS()
+	SetFileSystem(fsys)
+	defer SetFileSystem(nil)
+	fs := newFileSink()
+	onlySink(t, fs)
+	SetSanitize(SanitizeStrip)
+	defer SetSanitize(SanitizeOff)
+
+	if err := NewLogger("debug").ToSnake("ctrl").InfoSync("bad\x00name \x1b[1mbold\x1b[0m"); err != nil {
+		t.Fatal(err)
+	}
+	b, _ := fsys.ReadFile(fs.dir() + "/ctrl.log")
+	if !strings.Contains(string(b), "badname bold") {
+		t.Errorf("ctrl.log = %q, want the control bytes stripped", b)
+	}
+}
+
+func TestFixUTF8(t *testing.T) {
+	tests := []struct {
+		name string
+		mode UTF8Mode
+		in   string
+		want string
+	}{
+		{"keep", UTF8Keep, "ok\xffbad", "ok\xffbad"},
+		{"valid is untouched", UTF8Replace, "caf\u00e9 \U0001F40D", "caf\u00e9 \U0001F40D"},
+		{"replace", UTF8Replace, "ok\xffbad\xc3", "ok\uFFFDbad\uFFFD"},
+		{"replace each byte", UTF8Replace, "\xe2\x28\xa1", "\uFFFD(\uFFFD"},
+		{"escape", UTF8Escape, "ok\xffbad\xc3", `ok\xffbad\xc3`},
+		{"escape low byte", UTF8Escape, "a\x80\x05", `a\x80` + "\x05"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetInvalidUTF8(tt.mode)
+			defer SetInvalidUTF8(UTF8Keep)
+			if got := fixUTF8(tt.in); got != tt.want {
+				t.Errorf("fixUTF8(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInvalidUTF8GivesValidJSON(t *testing.T) {
+	for _, mode := range []UTF8Mode{UTF8Keep, UTF8Replace, UTF8Escape} {
+		SetInvalidUTF8(mode)
+		m, _ := NewLogger("debug").ToSnake("snake\xfe").buildLog(InfoLevel, "moved \xff\xc3(", time.Now(), nil)
+		SetInvalidUTF8(UTF8Keep)
+
+		line := JSONFormatter{}.Format(m)
+		if !json.Valid(line) {
+			t.Errorf("mode %d: invalid JSON %q", mode, line)
+		}
+	}
+}
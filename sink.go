@@ -0,0 +1,264 @@
+package snakeLoggerFile
+
+import (
+	"io"
+	"os"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Sink is somewhere log entries end up
+// the per snake files are one sink, a console mirror is another
+// Write is called from the writer goroutine, but a sink that is also used
+// elsewhere needs to do its own locking. Write must never log through this
+// package, the writer would end up waiting on itself once the queue is full
+// RemoveSink and SetSinkLevel find a sink with ==, so one that isn't
+// comparable, like a struct holding a slice or map, has to be added as a pointer
+type Sink interface {
+	Write(l LogData) error
+}
+
+// sinkEntry pairs a sink with the lowest level it wants
+type sinkEntry struct {
+	sink  Sink
+	level SnakeLoggerLevel
+	brk   *breaker
+}
+
+var (
+	sinksMu sync.RWMutex
+	sinks   []sinkEntry
+	// fileSinkLevel is the level of the default file sink in sinks, -1 when
+	// it was removed, kept apart so it can be read while dispatch holds sinksMu
+	fileSinkLevel = int32(DebugLevel)
+)
+
+// sameSink is a == b, but false instead of a panic when both are the
+// same type that can't be compared
+func sameSink(a, b Sink) bool {
+	t := reflect.TypeOf(a)
+	if t != reflect.TypeOf(b) || (t != nil && !t.Comparable()) {
+		return false
+	}
+	return a == b
+}
+
+// updateFileSinkLevel sets fileSinkLevel from sinks, sinksMu must be held
+func updateFileSinkLevel() {
+	level := int32(-1)
+	for _, se := range sinks {
+		if sameSink(se.sink, defaultFileSink) {
+			level = int32(se.level)
+		}
+	}
+	atomic.StoreInt32(&fileSinkLevel, level)
+}
+
+// fileSinkGets says if dispatch gives entries at level to the default file sink
+func fileSinkGets(level SnakeLoggerLevel) bool {
+	min := atomic.LoadInt32(&fileSinkLevel)
+	return min >= 0 && !level.below(SnakeLoggerLevel(min))
+}
+
+// AddSink sends every entry at level or above to s as well as the files
+// there are two gates: the logger's own level decides if an entry is made
+// at all, then each sink's level decides if that sink gets it
+// so a sink can only be quieter than the logger, never louder:
+// a debug logger with the files at DebugLevel and a HEC sink at WarnLevel
+// keeps everything local and only ships warnings and up
+// entries below a sink's level are dropped before the sink sees them,
+// so a RetrySink never even queues them
+func AddSink(s Sink, level SnakeLoggerLevel) {
+	sinksMu.Lock()
+	sinks = append(sinks, sinkEntry{sink: s, level: level, brk: &breaker{}})
+	updateFileSinkLevel()
+	sinksMu.Unlock()
+}
+
+// RemoveSink stops sending entries to s, what it buffered is flushed
+// removing DefaultFileSink() leaves only the sinks added with AddSink,
+// for containers where nothing can be written under $HOME or /tmp
+// false if s was never added
+func RemoveSink(s Sink) bool {
+	sinksMu.Lock()
+	found := false
+	for i := range sinks {
+		if sameSink(sinks[i].sink, s) {
+			sinks = append(sinks[:i:i], sinks[i+1:]...)
+			found = true
+			break
+		}
+	}
+	updateFileSinkLevel()
+	sinksMu.Unlock()
+	if !found {
+		return false
+	}
+	if f, ok := s.(flusher); ok {
+		if err := f.Flush(); err != nil {
+			recordError(err)
+		}
+	}
+	return true
+}
+
+// DefaultFileSink is the sink writing the per snake files, the one the
+// package level file settings like SetRotation and SetBaseDir change
+func DefaultFileSink() Sink {
+	return defaultFileSink
+}
+
+// NewFileSink returns a sink writing per snake files into dir, the same
+// way the default one does, to add with AddSink. It doesn't follow the
+// package level file settings, give it its own rotation and file layout
+// with SetSinkRotation and SetSinkFileLayout. Only the file system
+// (SetFileSystem) and the formatter are shared
+func NewFileSink(dir string) Sink {
+	fs := newFileSink()
+	fs.fixedDir = dir
+	return fs
+}
+
+// SetSinkLevel changes the minimum level of a sink that was already added
+func SetSinkLevel(s Sink, level SnakeLoggerLevel) {
+	sinksMu.Lock()
+	for i := range sinks {
+		if sameSink(sinks[i].sink, s) {
+			sinks[i].level = level
+		}
+	}
+	updateFileSinkLevel()
+	sinksMu.Unlock()
+}
+
+// SinkLevel returns the minimum level for s, false if s was never added
+func SinkLevel(s Sink) (SnakeLoggerLevel, bool) {
+	sinksMu.RLock()
+	defer sinksMu.RUnlock()
+	for _, se := range sinks {
+		if sameSink(se.sink, s) {
+			return se.level, true
+		}
+	}
+	return DebugLevel, false
+}
+
+// SetFileLevel sets the minimum level that gets written to the log files
+// the default is DebugLevel, so the files get everything the loggers let through
+func SetFileLevel(level SnakeLoggerLevel) {
+	SetSinkLevel(defaultFileSink, level)
+}
+
+// dispatch hands an entry to every sink that wants it
+// every error is recorded, the first one is returned
+func dispatch(m LogData) error {
+	sinksMu.RLock()
+	defer sinksMu.RUnlock()
+	if atomic.LoadInt32(&diverting) > 0 {
+		// a StartCaptureOnly capture takes the entry instead
+		notifyObservers(m)
+		return nil
+	}
+	var first error
+	now := time.Now()
+	for _, se := range sinks {
+		if m.Level.below(se.level) {
+			continue
+		}
+		if !se.brk.allow(now) {
+			recordSkip()
+			continue
+		}
+		err := se.sink.Write(m)
+		if err != nil {
+			err = writeFailed(se.sink, m, err)
+		}
+		se.brk.result(err, now, se.sink)
+		if err != nil {
+			recordError(err)
+			if first == nil {
+				first = err
+			}
+		}
+	}
+	notifyObservers(m)
+	return first
+}
+
+// flusher is a sink that buffers and needs to be told to write out
+type flusher interface {
+	Flush() error
+}
+
+// flushSinks flushes every sink that buffers
+// every error is recorded, the first one is returned
+func flushSinks() error {
+	sinksMu.RLock()
+	defer sinksMu.RUnlock()
+	var first error
+	for _, se := range sinks {
+		if f, ok := se.sink.(flusher); ok {
+			if err := f.Flush(); err != nil {
+				recordError(err)
+				if first == nil {
+					first = err
+				}
+			}
+		}
+	}
+	return first
+}
+
+// WriterSink formats entries and writes them to any io.Writer
+type WriterSink struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewWriterSink returns a sink writing to w
+func NewWriterSink(w io.Writer) *WriterSink {
+	return &WriterSink{w: w}
+}
+
+// NewStdoutSink returns a sink for mirroring logs to the console
+func NewStdoutSink() *WriterSink {
+	return NewWriterSink(os.Stdout)
+}
+
+// NewStderrSink returns a sink writing to stderr
+func NewStderrSink() *WriterSink {
+	return NewWriterSink(os.Stderr)
+}
+
+// Write implements Sink
+func (ws *WriterSink) Write(l LogData) error {
+	bp := getBuffer()
+	b := appendFormat((*bp)[:0], l)
+	ws.mu.Lock()
+	_, err := ws.w.Write(b)
+	ws.mu.Unlock()
+	putBuffer(bp, b)
+	return err
+}
+
+// DiscardSink formats every entry and throws the bytes away
+// useful for measuring formatting cost without any I/O
+type DiscardSink struct{}
+
+// Write implements Sink
+func (DiscardSink) Write(l LogData) error {
+	bp := getBuffer()
+	putBuffer(bp, appendFormat((*bp)[:0], l))
+	return nil
+}
+
+// SetDiscard keeps the whole pipeline running, levels, formatting and all,
+// but the log files are never touched: each entry is formatted then dropped
+// other sinks still get their entries
+func SetDiscard(on bool) {
+	defaultFileSink.mu.Lock()
+	defaultFileSink.discard = on
+	defaultFileSink.mu.Unlock()
+}
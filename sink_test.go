@@ -0,0 +1,82 @@
+package snakeLoggerFile
+
+import (
+	"testing"
+)
+
+// sliceSink can't be compared with ==, it holds a slice
+type sliceSink struct {
+	got *[]string
+	tag []string
+}
+
+func (s sliceSink) Write(l LogData) error {
+	*s.got = append(*s.got, l.Msg)
+	return nil
+}
+
+func TestUncomparableSink(t *testing.T) {
+	var got []string
+	a := sliceSink{got: &got, tag: []string{"a"}}
+	b := sliceSink{got: &got, tag: []string{"b"}}
+	AddSink(a, DebugLevel)
+
+	// none of these may panic, and a value sink can't be found again
+	SetSinkLevel(b, WarnLevel)
+	if _, ok := SinkLevel(b); ok {
+		t.Error("SinkLevel found a sink that was never added")
+	}
+	if RemoveSink(a) {
+		t.Error("RemoveSink found an uncomparable sink")
+	}
+
+	// by pointer it works as normal
+	p := &sliceSink{got: &got}
+	AddSink(p, DebugLevel)
+	SetSinkLevel(p, WarnLevel)
+	if level, ok := SinkLevel(p); !ok || level != WarnLevel {
+		t.Errorf("SinkLevel(p) = %v, %v, want WarnLevel", level, ok)
+	}
+	if !RemoveSink(p) {
+		t.Error("RemoveSink didn't find the pointer sink")
+	}
+
+	// take the value sink back out for the tests after this one
+	sinksMu.Lock()
+	for i := range sinks {
+		if _, ok := sinks[i].sink.(sliceSink); ok {
+			sinks = append(sinks[:i:i], sinks[i+1:]...)
+			break
+		}
+	}
+	updateFileSinkLevel()
+	sinksMu.Unlock()
+}
+
+// sliceFS is a FileSystem that isn't comparable
+type sliceFS struct {
+	*MemFS
+	tag []string
+}
+
+func TestUncomparableFileSystem(t *testing.T) {
+	fsys := sliceFS{MemFS: NewMemFS(), tag: []string{"x"}}
+	SetFileSystem(fsys)
+	SetBaseDir("/logs")
+	defer func() {
+		Sync()
+		SetFileSystem(NewMemFS())
+		SetBaseDir("")
+	}()
+
+	l := NewLogger("debug").ToSnake("odd")
+	l.Info("one")
+	l.Info("two")
+	if lines := readLog(t, fsys.MemFS, "odd.log"); len(lines) != 2 {
+		t.Errorf("odd.log = %q, want both entries", lines)
+	}
+	// the handle is kept between writes, not reopened every time
+	if n := fsys.OpenCount(); n != 1 {
+		t.Errorf("%d handles open, want the one", n)
+	}
+}
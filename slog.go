@@ -0,0 +1,108 @@
+//go:build go1.21
+// +build go1.21
+
+package snakeLoggerFile
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// SlogHandler is a slog.Handler that writes through a SnakeLogger, so
+// code and libraries using log/slog end up in the same per snake files
+// attributes become fields, a group's attributes are named "group.key"
+type SlogHandler struct {
+	logger *SnakeLogger
+	fields map[string]interface{}
+	prefix string
+}
+
+// NewSlogHandler returns a handler logging through logger,
+// use it with slog.New(NewSlogHandler(logger))
+func NewSlogHandler(logger *SnakeLogger) *SlogHandler {
+	return &SlogHandler{logger: logger}
+}
+
+// slogLevel maps a slog level onto the nearest SnakeLoggerLevel
+// anything past slog.LevelError+4 is a report
+func slogLevel(l slog.Level) SnakeLoggerLevel {
+	switch {
+	case l < slog.LevelInfo:
+		return DebugLevel
+	case l < slog.LevelWarn:
+		return InfoLevel
+	case l < slog.LevelError:
+		return WarnLevel
+	case l < slog.LevelError+4:
+		return ErrorLevel
+	default:
+		return ReportLevel
+	}
+}
+
+// Enabled implements slog.Handler
+func (h *SlogHandler) Enabled(_ context.Context, l slog.Level) bool {
+	return h.logger.enabled(slogLevel(l))
+}
+
+// Handle implements slog.Handler
+func (h *SlogHandler) Handle(_ context.Context, r slog.Record) error {
+	level := slogLevel(r.Level)
+	t := r.Time
+	if t.IsZero() {
+		t = time.Now()
+	}
+	var fields map[string]interface{}
+	if r.NumAttrs() > 0 {
+		fields = make(map[string]interface{}, r.NumAttrs())
+		r.Attrs(func(a slog.Attr) bool {
+			addAttr(fields, h.prefix, a)
+			return true
+		})
+	}
+	// the first caller outside this package is in log/slog, the record knows the real one
+	h.logger.send(level, r.Message, t, mergeFields(h.fields, fields, stackFields(level), originFieldsPC(r.PC)))
+	return nil
+}
+
+// WithAttrs implements slog.Handler
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	fields := make(map[string]interface{}, len(attrs))
+	for _, a := range attrs {
+		addAttr(fields, h.prefix, a)
+	}
+	c := *h
+	c.fields = mergeFields(h.fields, fields)
+	return &c
+}
+
+// WithGroup implements slog.Handler
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	c := *h
+	c.prefix = h.prefix + name + "."
+	return &c
+}
+
+// addAttr puts a into fields under prefix, groups are flattened
+// and empty attributes are left out as slog asks
+func addAttr(fields map[string]interface{}, prefix string, a slog.Attr) {
+	v := a.Value.Resolve()
+	if v.Kind() == slog.KindGroup {
+		p := prefix
+		if a.Key != "" {
+			p += a.Key + "."
+		}
+		for _, ga := range v.Group() {
+			addAttr(fields, p, ga)
+		}
+		return
+	}
+	if a.Key == "" {
+		return
+	}
+	fields[prefix+a.Key] = v.Any()
+}
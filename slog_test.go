@@ -0,0 +1,29 @@
+//go:build go1.21
+// +build go1.21
+
+package snakeLoggerFile
+
+import (
+	"log/slog"
+	"testing"
+)
+
+func TestSlogHandlerOriginIsTheCaller(t *testing.T) {
+	SetOriginPackage(true)
+	defer SetOriginPackage(false)
+	l := NewTestLogger()
+	log := slog.New(NewSlogHandler(l.SnakeLogger)).WithGroup("req")
+
+	log.Warn("slow move", "ms", 420)
+	m, ok := l.LastEntry()
+	if !ok {
+		t.Fatal("nothing was logged")
+	}
+	if m.Level != WarnLevel || m.Msg != "slow move" || m.Fields["req.ms"] != int64(420) {
+		t.Errorf("entry %+v, want a warning with req.ms=420", m)
+	}
+	// the caller is this test, not log/slog
+	if m.Fields["package"] != "snakeLoggerFile" {
+		t.Errorf("package = %v, want snakeLoggerFile", m.Fields["package"])
+	}
+}
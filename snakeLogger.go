@@ -1,16 +1,16 @@
 package snakeLoggerFile
 
 import (
-	"errors"
 	"fmt"
-	"log"
-	"os"
-	"strings"
+	"sync/atomic"
 	"time"
 )
 
 var writeChan chan LogData
 
+// timestampFormat is used for the Timestamp field
+const timestampFormat = "2006-01-02T15:04:05.000000000"
+
 // SnakeLoggerLevel defines the levels
 type SnakeLoggerLevel uint8
 
@@ -39,29 +39,110 @@ var levelMap = map[SnakeLoggerLevel]string{
 }
 
 // LogData is the format for a log
+// the json tags are used by the JSONFormatter
 type LogData struct {
-	ID            string
-	Sev           string
-	Msg           string
-	Timestamp     string
-	UnixTimeStamp int64
-	Turn          int
-	Function      string
-	SnakeName     string
+	ID            string `json:"id"`
+	Sev           string `json:"sev"`
+	Msg           string `json:"msg"`
+	Timestamp     string `json:"timestamp"`
+	UnixTimeStamp int64  `json:"unix_timestamp"`
+	Turn          int    `json:"turn"`
+	Function      string `json:"function"`
+	SnakeName     string `json:"snake_name"`
+	// Instance is the snake name and logger index together, see SetInstanceFormat
+	Instance string `json:"instance,omitempty"`
+	// Fields is extra structured data, left out when there is none
+	Fields map[string]interface{} `json:"fields,omitempty"`
+	// Seq counts the entries written to one file, starting at 1
+	// it is 0 unless SetSequenceNumbers is on
+	Seq uint64 `json:"seq,omitempty"`
+	// Level is the numeric form of Sev, used by sinks to filter
+	Level SnakeLoggerLevel `json:"-"`
+
+	// formatter is the originating logger's choice, nil means the package default
+	formatter Formatter
+	// raw is written as is instead of being formatted, see Raw
+	raw []byte
+	// op is run by the writer instead of writing anything, see runOnWriter
+	op func()
 }
 
 // SnakeLogger is a custom logger for tracking battlesnakes
 type SnakeLogger struct {
-	level       SnakeLoggerLevel
+	// currentTurn is read and written atomically, it is first so it stays
+	// 64 bit aligned on 32 bit platforms
+	currentTurn int64
+	// level is a SnakeLoggerLevel, kept as uint32 so it can be changed atomically
+	level       uint32
 	isNull      bool
 	id          string
 	currentFunc string
-	currentTurn int
 	name        string
+	index       uint64
+	formatter   Formatter
+	// staticFields are set once at construction, fields come from WithField
+	// neither map is changed after it is set, children share them
+	staticFields map[string]interface{}
+	fields       map[string]interface{}
+	// start is when elapsed_ms counts from, zero means it is off
+	start time.Time
+	// rec takes the entries instead of the writer, see NewTestLogger
+	rec *recorder
+	// game collects stats between StartGame and EndGame
+	game *gameStats
+	// limits holds the limitSet from SetSampling and SetRateLimit
+	// it is swapped while other goroutines may be logging
+	limits atomic.Value
+	// pinned is 1 while a WithTemporaryLevel level wins over the overrides
+	pinned int32
 }
 
 func (s *SnakeLogger) updateLogLevel(l SnakeLoggerLevel) {
-	s.level = l
+	atomic.StoreUint32(&s.level, uint32(l))
+}
+
+func (s *SnakeLogger) getLogLevel() SnakeLoggerLevel {
+	return SnakeLoggerLevel(atomic.LoadUint32(&s.level))
+}
+
+// clone copies the logger so the copy can change its context without touching s
+func (s *SnakeLogger) clone() *SnakeLogger {
+	c := &SnakeLogger{
+		level:        atomic.LoadUint32(&s.level),
+		isNull:       s.isNull,
+		id:           s.id,
+		currentFunc:  s.currentFunc,
+		currentTurn:  atomic.LoadInt64(&s.currentTurn),
+		name:         s.name,
+		index:        s.index,
+		formatter:    s.formatter,
+		staticFields: s.staticFields,
+		fields:       s.fields,
+		start:        s.start,
+		rec:          s.rec,
+		game:         s.game,
+		pinned:       atomic.LoadInt32(&s.pinned),
+	}
+	if ls := s.loadLimits(); ls != nil {
+		c.limits.Store(ls)
+	}
+	return c
+}
+
+// WithTemporaryLevel returns a child logger at level, for debugging one block of code
+// the parent and anyone else sharing it are not changed
+// until restore the level wins over SetGlobalLevel and SetSnakeLevel
+// restore puts the child back to the parent's level, so it can be deferred
+func (s *SnakeLogger) WithTemporaryLevel(level SnakeLoggerLevel) (*SnakeLogger, func()) {
+	c := s.clone()
+	prev := c.getLogLevel()
+	prevPinned := atomic.LoadInt32(&c.pinned)
+	c.updateLogLevel(level)
+	atomic.StoreInt32(&c.pinned, 1)
+	return c, func() {
+		c.updateLogLevel(prev)
+		atomic.StoreInt32(&c.pinned, prevPinned)
+	}
 }
 
 func (s *SnakeLogger) UpdateID(newid string) {
@@ -81,110 +162,269 @@ func (s *SnakeLogger) GetFunc() string {
 }
 
 func (s *SnakeLogger) UpdateTurn(t int) {
-	s.currentTurn = t
+	atomic.StoreInt64(&s.currentTurn, int64(t))
+	if s.game != nil {
+		s.game.turnStarted(t)
+	}
 }
 
-// parseLog builds a struct for the log
-//   then puts that struct on a channel for the file writer
-func (s *SnakeLogger) parseLog(level SnakeLoggerLevel, msg string, t time.Time) {
-	var thisLog LogData
+// NextTurn moves the logger on one turn and returns the new turn
+// it is safe to call while other goroutines are logging
+func (s *SnakeLogger) NextTurn() int {
+	t := int(atomic.AddInt64(&s.currentTurn, 1))
+	if s.game != nil {
+		s.game.turnStarted(t)
+	}
+	return t
+}
 
-	if s.level > level {
-		return
+// ResetTurn puts the turn back to 0, for the start of a new game
+func (s *SnakeLogger) ResetTurn() {
+	atomic.StoreInt64(&s.currentTurn, 0)
+}
+
+// StartElapsed adds an elapsed_ms field to every entry from now on,
+// counted from this call, call it again at the start of each game
+// children made afterwards count from the same start
+func (s *SnakeLogger) StartElapsed() {
+	s.start = time.Now()
+}
+
+// UpdateIndex sets the number that tells apart loggers with the same name
+func (s *SnakeLogger) UpdateIndex(i uint64) {
+	s.index = i
+}
+
+// UpdateFormatter sets the output format for just this logger
+// nil goes back to whatever SetFormatter chose for the package
+func (s *SnakeLogger) UpdateFormatter(f Formatter) {
+	s.formatter = f
+}
+
+// enabled is checked before any formatting work is done,
+// so a call below the logger's level costs next to nothing
+// a null logger drops everything, even an entry logged at NullLevel itself
+// SetSnakeLevel and SetGlobalLevel can move the level of any other logger
+func (s *SnakeLogger) enabled(level SnakeLoggerLevel) bool {
+	min := s.getLogLevel()
+	if min == NullLevel || level == NullLevel {
+		return false
+	}
+	if atomic.LoadInt32(&s.pinned) == 0 {
+		min = effectiveLevel(s.name, min)
 	}
+	return !level.below(min) && !isDryRun()
+}
 
-	timestamp := t.Format("2006-01-02T15:04:05.000000000")
-	unixstamp := t.UnixNano()
+// newEntry fills in a LogData from the logger's current context
+func (s *SnakeLogger) newEntry(level SnakeLoggerLevel, msg string, t time.Time, fields map[string]interface{}) LogData {
+	timestamp := formatTimestamp(t)
+	unixstamp := unixTime(t)
 
-	thisLog = LogData{
+	if !s.start.IsZero() {
+		fields = mergeFields(fields, map[string]interface{}{"elapsed_ms": t.Sub(s.start).Milliseconds()})
+	}
+
+	return LogData{
 		Msg:           msg,
 		Timestamp:     timestamp,
 		UnixTimeStamp: unixstamp,
 		ID:            s.id,
-		Sev:           levelMap[level],
-		Turn:          s.currentTurn,
+		Sev:           levelName(level),
+		Level:         level,
+		Turn:          int(atomic.LoadInt64(&s.currentTurn)),
 		Function:      s.currentFunc,
 		SnakeName:     s.name,
+		Instance:      instanceName(s.name, s.index),
+		Fields:        capFields(mergeFields(s.staticFields, s.fields, fields)),
+		formatter:     s.formatter,
+	}
+}
+
+// parseLog builds a struct for the log
+//   then puts that struct on a channel for the file writer
+func (s *SnakeLogger) parseLog(level SnakeLoggerLevel, msg string, t time.Time, fields map[string]interface{}) {
+	if !s.enabled(level) || !s.sampled(level, t) {
+		return
+	}
+	if thisLog, ok := s.buildLog(level, msg, t, fields); ok {
+		if s.game != nil {
+			s.game.logged(level)
+		}
+		s.deliver(thisLog)
+	}
+
+}
+
+// buildLog is newEntry plus the handling of generic entries
+// it says false when the entry is generic and below SetGenericLevel
+func (s *SnakeLogger) buildLog(level SnakeLoggerLevel, msg string, t time.Time, fields map[string]interface{}) (LogData, bool) {
+	generic := hasGenericPrefix(msg)
+	if (generic || s.name == "") && level.below(genericLevel()) {
+		return LogData{}, false
+	}
+	countLevel(level)
+	msg = fixUTF8(sanitize(msg))
+	thisLog := s.newEntry(level, msg, t, fields)
+	if name := fixUTF8(thisLog.SnakeName); name != thisLog.SnakeName {
+		thisLog.SnakeName = name
+		thisLog.Instance = instanceName(name, s.index)
 	}
 
 	// add in ability to write to generic log from anywhere
 	// start the message with GENERIC
-	if strings.HasPrefix(msg, "GENERIC") {
-		thisLog.Msg = msg[8:]
+	// deprecated, use Generic instead
+	if generic {
+		// the prefix is followed by one separator, or nothing at all
+		rest := msg[len("GENERIC"):]
+		if rest != "" {
+			rest = rest[1:]
+		}
+		thisLog.Msg = rest
 		thisLog.ID = ""
+		thisLog = tagGeneric(thisLog)
+	} else if thisLog.SnakeName == "" {
+		thisLog = tagGeneric(thisLog)
 	}
-	writeChan <- thisLog
+	return thisLog, true
+}
 
+// send builds the entry and queues it on the caller's goroutine, so entries
+// from one goroutine are written in the order they were made
+// only the writing is left to the writer. It is tracked so Close can wait
+// for it, nothing happens after Close
+// with the Block overflow policy a full queue holds the caller up, so
+// sinks and observers must not log with it (they would wait on themselves)
+func (s *SnakeLogger) send(level SnakeLoggerLevel, msg string, t time.Time, fields map[string]interface{}) {
+	if s.rec != nil {
+		s.parseLog(level, msg, t, fields)
+		return
+	}
+	gen, ok := beginSend()
+	if !ok {
+		return
+	}
+	defer endSend(gen)
+	s.parseLog(level, msg, t, fields)
+}
+
+// deliver hands a finished entry to the writer, or to the recorder of a test logger
+func (s *SnakeLogger) deliver(m LogData) {
+	if s.rec != nil {
+		s.rec.add(m)
+		return
+	}
+	enqueue(m)
+}
+
+// logFields is the common path for helpers that attach fields to an entry
+func (s *SnakeLogger) logFields(level SnakeLoggerLevel, msg string, fields map[string]interface{}) {
+	if !s.enabled(level) {
+		return
+	}
+	now := time.Now()
+	if origin := originFields(); origin != nil {
+		fields = mergeFields(fields, origin)
+	}
+	s.send(level, msg, now, fields)
 }
 
 func (s *SnakeLogger) Debugf(format string, v ...interface{}) {
+	if !s.enabled(DebugLevel) {
+		return
+	}
 	now := time.Now()
 	msg := fmt.Sprintf(format, v...)
-	go s.parseLog(DebugLevel, msg, now)
+	s.send(DebugLevel, msg, now, callFields(DebugLevel))
 
 }
 
 func (s *SnakeLogger) Infof(format string, v ...interface{}) {
+	if !s.enabled(InfoLevel) {
+		return
+	}
 	now := time.Now()
 	msg := fmt.Sprintf(format, v...)
-	go s.parseLog(InfoLevel, msg, now)
+	s.send(InfoLevel, msg, now, callFields(InfoLevel))
 }
 
 func (s *SnakeLogger) Warnf(format string, v ...interface{}) {
+	if !s.enabled(WarnLevel) {
+		return
+	}
 	now := time.Now()
 	msg := fmt.Sprintf(format, v...)
-	go s.parseLog(WarnLevel, msg, now)
+	s.send(WarnLevel, msg, now, callFields(WarnLevel))
 }
 
 func (s *SnakeLogger) Errorf(format string, v ...interface{}) {
+	if !s.enabled(ErrorLevel) {
+		return
+	}
 	now := time.Now()
 	msg := fmt.Sprintf(format, v...)
-	go s.parseLog(ErrorLevel, msg, now)
+	s.send(ErrorLevel, msg, now, callFields(ErrorLevel))
 }
 
 func (s *SnakeLogger) Reportf(format string, v ...interface{}) {
+	if !s.enabled(ReportLevel) {
+		return
+	}
 	now := time.Now()
 	msg := fmt.Sprintf(format, v...)
-	go s.parseLog(ReportLevel, msg, now)
+	s.send(ReportLevel, msg, now, callFields(ReportLevel))
 }
 
 func (s *SnakeLogger) Debug(m string) {
+	if !s.enabled(DebugLevel) {
+		return
+	}
 	now := time.Now()
-	go s.parseLog(DebugLevel, m, now)
+	s.send(DebugLevel, m, now, callFields(DebugLevel))
 }
 
 func (s *SnakeLogger) Info(m string) {
+	if !s.enabled(InfoLevel) {
+		return
+	}
 	now := time.Now()
-	go s.parseLog(InfoLevel, m, now)
+	s.send(InfoLevel, m, now, callFields(InfoLevel))
 }
 
 func (s *SnakeLogger) Warn(m string) {
+	if !s.enabled(WarnLevel) {
+		return
+	}
 	now := time.Now()
-	go s.parseLog(WarnLevel, m, now)
+	s.send(WarnLevel, m, now, callFields(WarnLevel))
 }
 
 func (s *SnakeLogger) Error(m string) {
+	if !s.enabled(ErrorLevel) {
+		return
+	}
 	now := time.Now()
-	go s.parseLog(ErrorLevel, m, now)
+	s.send(ErrorLevel, m, now, callFields(ErrorLevel))
 }
 
 func (s *SnakeLogger) Report(m string) {
+	if !s.enabled(ReportLevel) {
+		return
+	}
 	now := time.Now()
-	go s.parseLog(ReportLevel, m, now)
+	s.send(ReportLevel, m, now, callFields(ReportLevel))
 }
 
 //NewLogger returns a new copy of the local logger
 func NewLogger(level string) *SnakeLogger {
 
 	s := SnakeLogger{
-		level: InfoLevel,
+		level: uint32(InfoLevel),
 		id:    "",
+		index: nextIndex(),
 	}
-	for l, v := range levelMap {
-		if v == level {
-			s.level = l
-			break
-		}
+	if l, ok := levelByName(level); ok {
+		s.level = uint32(l)
 	}
 	return &s
 }
@@ -201,63 +441,72 @@ func (s *SnakeLogger) UpdateName(n string) {
 // this is different than how it was working before (one file per game)
 // since this will be read by splunk, we don't need new files
 func writeToFile(c chan LogData) {
-	// make sure path is setup
-	// find home directory, since I am running this on similar linux systems, this should be all we need
-	var (
-		dir      string
-		basedir  string
-		filename string
-		err      error
-	)
-	dir = os.Getenv("HOME")
-	if dir == "" {
-		fmt.Println("cannot get home dir, sending to tmp")
-		dir = "/tmp"
-	}
-	basedir = dir + "/battlesnakeLogs"
-	err = os.Mkdir(basedir, 0755)
-	if err != nil {
-		if !errors.Is(err, os.ErrExist) {
-			fmt.Println("I don't understand this error: ", err)
-		}
-	}
-
+	lastFlush := time.Now()
 	for m := range c {
-		if m.SnakeName == "" {
-			filename = basedir + "/generic.log"
-		} else {
-			filename = fmt.Sprintf("%s/%s.log", basedir, m.SnakeName)
+		if m.op != nil {
+			m.op()
+			continue
 		}
-		f, err := os.OpenFile(filename, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-		if err != nil {
-			log.Fatal(err)
+		writeEntry(m)
+		checkBackpressure(len(c))
+		// nothing else waiting, so this is a good time to get the buffers on disk
+		// under steady load the flush interval makes sure it still happens
+		if len(c) == 0 || flushDue(lastFlush) {
+			flushSinks()
+			lastFlush = time.Now()
 		}
+	}
+	stopWriter()
+}
 
-		if _, err := f.Write(m.Bytes()); err != nil {
-			fmt.Println(err)
-			break
-		}
-		cerr := f.Close()
-		if cerr != nil {
-			fmt.Println(cerr)
+// writeEntry does the writer's work for one entry, returning the first error
+// it must only be called on the writer goroutine
+func writeEntry(m LogData) error {
+	atomic.AddUint64(&entriesSeen, 1)
+	timed := timingEnabled()
+	var start time.Time
+	if timed {
+		start = time.Now()
+	}
+	var first error
+	ep := getEntries()
+	entries := collapse((*ep)[:0], redactEntry(fixEntry(m)))
+	for _, e := range entries {
+		if err := safeDispatch(e); err != nil && first == nil {
+			first = err
 		}
-
 	}
+	putEntries(ep, entries)
+	if timed {
+		recordTiming(time.Since(start))
+	}
+	return first
 }
 
 // String returns a nice clean string for the log
 func (l LogData) String() string {
-	res := fmt.Sprintf("%s %s (%v) <%s> [%s] %s\n", l.Timestamp, l.ID, l.Turn, l.Function, l.Sev, l.Msg)
-	return res
+	bp := getBuffer()
+	b := TextFormatter{}.AppendFormat((*bp)[:0], l)
+	str := string(b)
+	putBuffer(bp, b)
+	return str
 }
 
 // Bytes returns a string representation, but in bytes
 func (l LogData) Bytes() []byte {
-	return []byte(l.String())
+	return TextFormatter{}.Format(l)
+}
+
+// JSON returns the entry as a JSON line, newline included
+// empty strings like the ID of a GENERIC entry are written as ""
+func (l LogData) JSON() []byte {
+	return JSONFormatter{}.Format(l)
 }
 
 func init() {
-	writeChan = make(chan LogData)
+	defaultFileSink = newFileSink()
+	sinks = []sinkEntry{{sink: defaultFileSink, level: DebugLevel, brk: &breaker{}}}
+	writeChan = make(chan LogData, defaultQueueSize)
 	go writeToFile(writeChan)
 
 }
@@ -0,0 +1,27 @@
+// Package snakelogtest has helpers for tests in packages that log
+// through snakeLoggerFile, kept apart so programs don't import testing
+package snakelogtest
+
+import (
+	"testing"
+
+	"github.com/papaburgs/snakeLoggerFile"
+)
+
+// FlushForTest waits for the logger to write everything queued so far,
+// and again when the test finishes, so assertions on the log files and
+// the next test don't race the writer
+// it can also be deferred, or called again before checking a file
+func FlushForTest(t testing.TB) {
+	t.Helper()
+	flush(t)
+	t.Cleanup(func() {
+		flush(t)
+	})
+}
+
+func flush(t testing.TB) {
+	if err := snakeLoggerFile.Sync(); err != nil {
+		t.Errorf("flushing logs: %v", err)
+	}
+}
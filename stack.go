@@ -0,0 +1,34 @@
+package snakeLoggerFile
+
+import (
+	"runtime"
+	"sync/atomic"
+)
+
+// maxStackSize caps how much of the stack is kept
+const maxStackSize = 16 * 1024
+
+var captureStacks int32
+
+// SetStackTraces attaches the calling goroutine's stack as the "stack" field
+// on every Error and Report entry
+// it is off by default because getting the stack is slow
+func SetStackTraces(on bool) {
+	var v int32
+	if on {
+		v = 1
+	}
+	atomic.StoreInt32(&captureStacks, v)
+}
+
+// stackFields returns the fields for an entry at level
+// it has to run on the caller's goroutine, the writer goroutine's stack
+// would say nothing about where the error came from
+func stackFields(level SnakeLoggerLevel) map[string]interface{} {
+	if level.below(ErrorLevel) || atomic.LoadInt32(&captureStacks) == 0 {
+		return nil
+	}
+	buf := make([]byte, maxStackSize)
+	n := runtime.Stack(buf, false)
+	return map[string]interface{}{"stack": string(buf[:n])}
+}
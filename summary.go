@@ -0,0 +1,121 @@
+package snakeLoggerFile
+
+import (
+	"sync"
+	"time"
+)
+
+// EventGameSummary is the event field of the record EndGame writes
+const EventGameSummary = "game_summary"
+
+// gameStats is what StartGame collects until EndGame
+// children of the logger share it, so their entries count too
+type gameStats struct {
+	mu      sync.Mutex
+	id      string
+	started time.Time
+	// turn is the turn being played, since when, and if there is one yet
+	turn       int
+	turnAt     time.Time
+	inTurn     bool
+	turns      int
+	minTurn    time.Duration
+	maxTurn    time.Duration
+	totalTurns time.Duration
+	warns      int
+	errors     int
+}
+
+// StartGame sets the logger's game id to id and starts collecting stats
+// for the game: turns played (each change of turn through UpdateTurn or
+// NextTurn starts one), warnings and errors logged, and the wall time of
+// every turn. EndGame writes them out. Children made from now on share
+// the stats, so use one logger per game when games run side by side
+func (s *SnakeLogger) StartGame(id string) {
+	s.id = id
+	s.game = &gameStats{id: id, started: time.Now()}
+}
+
+// EndGame writes one report entry with the stats collected since StartGame:
+// game_id, result, turns, last_turn, warns, errors, duration_ms and
+// turn_ms_min, turn_ms_avg and turn_ms_max, then stops collecting
+// nothing is written if StartGame wasn't called
+func (s *SnakeLogger) EndGame(result string) {
+	g := s.game
+	if g == nil {
+		return
+	}
+	s.game = nil
+	now := time.Now()
+	g.mu.Lock()
+	g.endTurn(now)
+	fields := map[string]interface{}{
+		"game_id":     g.id,
+		"result":      result,
+		"turns":       g.turns,
+		"last_turn":   g.turn,
+		"warns":       g.warns,
+		"errors":      g.errors,
+		"duration_ms": now.Sub(g.started).Milliseconds(),
+	}
+	if g.turns > 0 {
+		fields["turn_ms_min"] = ms(g.minTurn)
+		fields["turn_ms_avg"] = ms(g.totalTurns / time.Duration(g.turns))
+		fields["turn_ms_max"] = ms(g.maxTurn)
+	}
+	g.mu.Unlock()
+	s.event(EventGameSummary, "game summary", fields)
+}
+
+// ms is d in milliseconds, with the fraction kept for fast turns
+func ms(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// turnStarted is called when the logger's turn is set to t
+// setting the turn it is already on is not a new turn
+func (g *gameStats) turnStarted(t int) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.inTurn && t == g.turn {
+		return
+	}
+	now := time.Now()
+	g.endTurn(now)
+	g.turn = t
+	g.turnAt = now
+	g.inTurn = true
+}
+
+// endTurn adds the turn being played to the stats, g.mu must be held
+func (g *gameStats) endTurn(now time.Time) {
+	if !g.inTurn {
+		return
+	}
+	d := now.Sub(g.turnAt)
+	if g.turns == 0 || d < g.minTurn {
+		g.minTurn = d
+	}
+	if d > g.maxTurn {
+		g.maxTurn = d
+	}
+	g.totalTurns += d
+	g.turns++
+	g.inTurn = false
+}
+
+// logged counts an entry made at level
+func (g *gameStats) logged(level SnakeLoggerLevel) {
+	switch level {
+	case WarnLevel, ErrorLevel:
+	default:
+		return
+	}
+	g.mu.Lock()
+	if level == WarnLevel {
+		g.warns++
+	} else {
+		g.errors++
+	}
+	g.mu.Unlock()
+}
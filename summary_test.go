@@ -0,0 +1,36 @@
+package snakeLoggerFile
+
+import (
+	"sync/atomic"
+	"testing"
+)
+
+func TestOneOffTurnsStayOutOfGameStats(t *testing.T) {
+	l := NewTestLogger()
+	l.StartGame("g1")
+	l.UpdateTurn(1)
+	l.Warn("close call")
+	l.UpdateTurn(2)
+
+	// these carry their own turn and must not start turns of the game
+	l.GameStart("g1", 0)
+	l.Decision(7, "up", map[string]float64{"up": 1})
+	l.Board(8, 1, 1, [][]rune{{'x'}})
+	l.GameEnd("g1", 9, "win")
+	l.EndGame("win")
+
+	m, ok := l.LastEntry()
+	if !ok || m.Fields["event"] != EventGameSummary {
+		t.Fatalf("last entry %+v, want the game summary", m)
+	}
+	if m.Fields["turns"] != 2 || m.Fields["last_turn"] != 2 || m.Fields["warns"] != 1 {
+		t.Errorf("summary turns=%v last_turn=%v warns=%v, want 2, 2 and 1",
+			m.Fields["turns"], m.Fields["last_turn"], m.Fields["warns"])
+	}
+	if reports := l.FilterByLevel(ReportLevel); len(reports) < 3 || reports[1].Turn != 7 {
+		t.Errorf("report entries %+v, want the decision on turn 7", reports)
+	}
+	if turn := atomic.LoadInt64(&l.currentTurn); turn != 2 {
+		t.Errorf("logger turn = %d, the one-off calls moved it", turn)
+	}
+}
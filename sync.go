@@ -0,0 +1,57 @@
+package snakeLoggerFile
+
+import "time"
+
+// logSync writes one entry and waits until it is flushed by every sink
+// the entry still goes through the writer, so it lands after anything
+// already queued, and the first write or flush error is returned
+func (s *SnakeLogger) logSync(level SnakeLoggerLevel, m string, fields map[string]interface{}) error {
+	if !s.enabled(level) {
+		return nil
+	}
+	entry, ok := s.buildLog(level, m, time.Now(), fields)
+	if !ok {
+		return nil
+	}
+	if s.game != nil {
+		s.game.logged(level)
+	}
+	if s.rec != nil {
+		s.rec.add(entry)
+		return nil
+	}
+	var err error
+	runOnWriter(func() {
+		err = writeEntry(entry)
+		if ferr := flushSinks(); err == nil {
+			err = ferr
+		}
+	})
+	return err
+}
+
+// DebugSync is Debug, but returns once the entry is on disk
+func (s *SnakeLogger) DebugSync(m string) error {
+	return s.logSync(DebugLevel, m, callFields(DebugLevel))
+}
+
+// InfoSync is Info, but returns once the entry is on disk
+func (s *SnakeLogger) InfoSync(m string) error {
+	return s.logSync(InfoLevel, m, callFields(InfoLevel))
+}
+
+// WarnSync is Warn, but returns once the entry is on disk
+func (s *SnakeLogger) WarnSync(m string) error {
+	return s.logSync(WarnLevel, m, callFields(WarnLevel))
+}
+
+// ErrorSync is Error, but returns once the entry is on disk,
+// use it right before a call that might crash the process
+func (s *SnakeLogger) ErrorSync(m string) error {
+	return s.logSync(ErrorLevel, m, callFields(ErrorLevel))
+}
+
+// ReportSync is Report, but returns once the entry is on disk
+func (s *SnakeLogger) ReportSync(m string) error {
+	return s.logSync(ReportLevel, m, callFields(ReportLevel))
+}
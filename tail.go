@@ -0,0 +1,33 @@
+package snakeLoggerFile
+
+import "sync"
+
+// tailBuffer is how many entries a TailLog reader can fall behind by
+const tailBuffer = 256
+
+// TailLog streams entries for snakeName ("" for the generic log) as the
+// writer handles them, like tail -f without reading the file back
+// entries are dropped for this reader rather than holding up the writer
+// when it falls more than tailBuffer behind
+// call cancel to stop, the channel is closed once it returns
+// ActiveFiles lists which snakes have files this session
+func TailLog(snakeName string) (<-chan LogData, func()) {
+	c := make(chan LogData, tailBuffer)
+	remove := AddObserver(func(l LogData) {
+		if l.SnakeName != snakeName {
+			return
+		}
+		select {
+		case c <- l:
+		default:
+		}
+	})
+	var once sync.Once
+	return c, func() {
+		once.Do(func() {
+			// once remove returns the observer can't be running, so closing is safe
+			remove()
+			close(c)
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package snakeLoggerFile
+
+import "sync"
+
+// TestLogger is a SnakeLogger for unit tests, every entry it and its
+// children make is kept in memory instead of going to the writer, so
+// nothing touches the file system, the sinks or the other loggers
+// entries are recorded by the log call itself, so they can be checked
+// as soon as it returns
+type TestLogger struct {
+	*SnakeLogger
+	rec *recorder
+}
+
+// recorder is where a TestLogger's entries go
+type recorder struct {
+	mu      sync.Mutex
+	entries []LogData
+}
+
+func (r *recorder) add(m LogData) {
+	r.mu.Lock()
+	r.entries = append(r.entries, m)
+	r.mu.Unlock()
+}
+
+// NewTestLogger returns a debug level logger named "test" that records
+// everything it logs
+func NewTestLogger() *TestLogger {
+	rec := &recorder{}
+	s := NewLogger("debug")
+	s.name = "test"
+	s.rec = rec
+	return &TestLogger{SnakeLogger: s, rec: rec}
+}
+
+// Entries is everything logged so far, oldest first
+func (t *TestLogger) Entries() []LogData {
+	t.rec.mu.Lock()
+	defer t.rec.mu.Unlock()
+	return append([]LogData(nil), t.rec.entries...)
+}
+
+// LastEntry is the latest entry, false if nothing has been logged
+func (t *TestLogger) LastEntry() (LogData, bool) {
+	t.rec.mu.Lock()
+	defer t.rec.mu.Unlock()
+	if len(t.rec.entries) == 0 {
+		return LogData{}, false
+	}
+	return t.rec.entries[len(t.rec.entries)-1], true
+}
+
+// FilterByLevel is the entries logged at exactly level, oldest first
+func (t *TestLogger) FilterByLevel(level SnakeLoggerLevel) []LogData {
+	t.rec.mu.Lock()
+	defer t.rec.mu.Unlock()
+	var res []LogData
+	for _, m := range t.rec.entries {
+		if m.Level == level {
+			res = append(res, m)
+		}
+	}
+	return res
+}
+
+// Reset forgets everything recorded so far
+func (t *TestLogger) Reset() {
+	t.rec.mu.Lock()
+	t.rec.entries = nil
+	t.rec.mu.Unlock()
+}
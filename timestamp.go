@@ -0,0 +1,62 @@
+package snakeLoggerFile
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// UnixPrecision is the unit of LogData.UnixTimeStamp
+type UnixPrecision int32
+
+const (
+	// UnixNanos is the default
+	UnixNanos UnixPrecision = iota
+	// UnixMicros is microseconds since the epoch
+	UnixMicros
+	// UnixMillis is milliseconds since the epoch
+	UnixMillis
+	// UnixSeconds is seconds since the epoch
+	UnixSeconds
+)
+
+var unixPrecision int32
+
+// SetUnixPrecision changes the unit of the unix timestamp on new entries
+// to match what the system reading the logs expects
+func SetUnixPrecision(p UnixPrecision) {
+	atomic.StoreInt32(&unixPrecision, int32(p))
+}
+
+// unixTime is t as a unix timestamp in the configured precision
+func unixTime(t time.Time) int64 {
+	switch UnixPrecision(atomic.LoadInt32(&unixPrecision)) {
+	case UnixMicros:
+		return t.UnixNano() / int64(time.Microsecond)
+	case UnixMillis:
+		return t.UnixNano() / int64(time.Millisecond)
+	case UnixSeconds:
+		return t.Unix()
+	default:
+		return t.UnixNano()
+	}
+}
+
+var utcTimestamps int32
+
+// SetUTC writes the Timestamp field in UTC instead of local time
+// the unix timestamp doesn't change, it has no zone
+func SetUTC(on bool) {
+	var v int32
+	if on {
+		v = 1
+	}
+	atomic.StoreInt32(&utcTimestamps, v)
+}
+
+// formatTimestamp is t as it goes in the Timestamp field
+func formatTimestamp(t time.Time) string {
+	if atomic.LoadInt32(&utcTimestamps) == 1 {
+		t = t.UTC()
+	}
+	return t.Format(timestampFormat)
+}
@@ -0,0 +1,90 @@
+package snakeLoggerFile
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// maxTimingSamples caps the samples kept between summaries
+const maxTimingSamples = 10000
+
+// timingOn is read by the writer for every entry, so it is atomic
+var timingOn int32
+
+var (
+	timingMu      sync.Mutex
+	timingSamples []time.Duration
+	timingStop    chan struct{}
+	// timingEvery is the summary interval, 0 when off
+	timingEvery time.Duration
+)
+
+// SetWriteTiming times how long the writer takes to format and write each
+// entry, and logs a p50/p95/p99 summary to the generic log every interval
+// an interval of 0 or less turns it off
+func SetWriteTiming(interval time.Duration) {
+	timingMu.Lock()
+	defer timingMu.Unlock()
+	if timingStop != nil {
+		close(timingStop)
+		timingStop = nil
+	}
+	timingSamples = nil
+	timingEvery = 0
+	if interval <= 0 {
+		atomic.StoreInt32(&timingOn, 0)
+		return
+	}
+	timingEvery = interval
+	atomic.StoreInt32(&timingOn, 1)
+	timingStop = make(chan struct{})
+	go reportTiming(interval, timingStop)
+}
+
+// timingEnabled is checked by the writer before it reads the clock
+func timingEnabled() bool {
+	return atomic.LoadInt32(&timingOn) == 1
+}
+
+// recordTiming keeps one write duration for the next summary
+func recordTiming(d time.Duration) {
+	timingMu.Lock()
+	if len(timingSamples) < maxTimingSamples {
+		timingSamples = append(timingSamples, d)
+	}
+	timingMu.Unlock()
+}
+
+func reportTiming(interval time.Duration, stop chan struct{}) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-t.C:
+			timingMu.Lock()
+			samples := timingSamples
+			timingSamples = nil
+			timingMu.Unlock()
+			if len(samples) == 0 {
+				continue
+			}
+			sort.Slice(samples, func(i, j int) bool { return samples[i] < samples[j] })
+			internalLogger.Infof("logger write latency n=%d p50=%s p95=%s p99=%s max=%s",
+				len(samples), percentile(samples, 50), percentile(samples, 95),
+				percentile(samples, 99), samples[len(samples)-1])
+		}
+	}
+}
+
+// percentile of sorted samples
+func percentile(sorted []time.Duration, p int) time.Duration {
+	i := (len(sorted)*p+99)/100 - 1
+	if i < 0 {
+		i = 0
+	}
+	return sorted[i]
+}
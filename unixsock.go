@@ -0,0 +1,48 @@
+package snakeLoggerFile
+
+import (
+	"net"
+	"sync"
+	"time"
+)
+
+// unixSocketTimeout bounds both the dial and each write
+const unixSocketTimeout = 2 * time.Second
+
+// unixSocketSink writes formatted lines to a unix domain socket
+// a failed write drops the connection and the next write dials again
+type unixSocketSink struct {
+	mu   sync.Mutex
+	path string
+	conn net.Conn
+}
+
+// NewUnixSocketSink sends every entry to the stream socket at path,
+// for a log shipper running next to the snake
+// delivery runs through a RetrySink, so while the socket is down entries
+// are retried and then written to the log files
+func NewUnixSocketSink(path string) *RetrySink {
+	return NewRetrySink(&unixSocketSink{path: path}, DefaultRetryPolicy)
+}
+
+// Write implements Sink
+func (u *unixSocketSink) Write(l LogData) error {
+	b := format(l)
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.conn == nil {
+		conn, err := net.DialTimeout("unix", u.path, unixSocketTimeout)
+		if err != nil {
+			return err
+		}
+		u.conn = conn
+	}
+	u.conn.SetWriteDeadline(time.Now().Add(unixSocketTimeout))
+	if _, err := u.conn.Write(b); err != nil {
+		u.conn.Close()
+		u.conn = nil
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package snakeLoggerFile
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestUnixSocketDownWritesEachEntryOnce(t *testing.T) {
+	tests := []struct {
+		name      string
+		fileLevel SnakeLoggerLevel
+	}{
+		// the files get the entries from dispatch, the spill must not add them again
+		{"files get entries", DebugLevel},
+		// the files skip info, so the spill is the only copy
+		{"files above entry level", WarnLevel},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fsys := useMemFS(t)
+			SetFileLevel(tt.fileLevel)
+			defer SetFileLevel(DebugLevel)
+
+			policy := RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+			r := NewRetrySink(&unixSocketSink{path: "/nonexistent/snake.sock"}, policy)
+			AddSink(r, DebugLevel)
+
+			l := NewLogger("debug").ToSnake("sockdown")
+			for i := 0; i < 3; i++ {
+				l.Infof("entry %d", i)
+			}
+			Sync()
+			RemoveSink(r)
+			r.Close()
+
+			lines := readLog(t, fsys, "sockdown.log")
+			for i := 0; i < 3; i++ {
+				if n := countContaining(lines, fmt.Sprintf("entry %d", i)); n != 1 {
+					t.Errorf("entry %d is in the file %d times, want 1:\n%v", i, n, lines)
+				}
+			}
+			if got := r.Spills(); got != 3 {
+				t.Errorf("Spills() = %d, want 3", got)
+			}
+		})
+	}
+}
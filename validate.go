@@ -0,0 +1,111 @@
+package snakeLoggerFile
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var dryRun int32
+
+// SetDryRun turns validate only mode on or off
+// while it is on, every log call is dropped before it reaches the writer,
+// so config can be checked with Validate without creating any log lines
+func SetDryRun(on bool) {
+	var v int32
+	if on {
+		v = 1
+	}
+	atomic.StoreInt32(&dryRun, v)
+}
+
+func isDryRun() bool {
+	return atomic.LoadInt32(&dryRun) == 1
+}
+
+// ParseLevel finds the level for a name like "debug" or "warn"
+// unlike NewLogger it returns an error instead of quietly using info
+func ParseLevel(level string) (SnakeLoggerLevel, error) {
+	if l, ok := levelByName(level); ok {
+		return l, nil
+	}
+	return InfoLevel, fmt.Errorf("unknown log level %q", level)
+}
+
+// NewLoggerChecked is NewLogger, but fails on a level it doesn't know
+func NewLoggerChecked(level string) (*SnakeLogger, error) {
+	if _, err := ParseLevel(level); err != nil {
+		return nil, err
+	}
+	return NewLogger(level), nil
+}
+
+// Validate checks the current package config without writing a log
+// it makes sure the log directory can be created and written to
+// and that the formatter produces a usable line
+func Validate() error {
+	var errs []string
+
+	fsys := currentFileSystem()
+	if err := checkDirWritable(fsys, logDir(fsys)); err != nil {
+		errs = append(errs, err.Error())
+	}
+
+	line := currentFormatter().Format(LogData{Sev: levelMap[InfoLevel], Msg: "validate"})
+	if len(line) == 0 || line[len(line)-1] != '\n' {
+		errs = append(errs, "formatter does not produce newline terminated lines")
+	}
+
+	if len(errs) > 0 {
+		return errors.New("invalid logger config: " + strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// checkDirWritable creates dir if needed and proves a file can be made in it
+func checkDirWritable(fsys FileSystem, dir string) error {
+	if err := fsys.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("cannot create log dir %s: %w", dir, err)
+	}
+	name := fmt.Sprintf("%s/.validate-%d", dir, os.Getpid())
+	f, err := fsys.OpenFile(name, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("log dir %s is not writable: %w", dir, err)
+	}
+	f.Close()
+	return fsys.Remove(name)
+}
+
+// malformedOnce makes sure the malformed entry warning is only printed once
+var malformedOnce sync.Once
+
+// fixEntry fills in anything a parser would choke on
+// a missing timestamp becomes the time the writer got the entry,
+// a missing severity comes from Level and a negative turn becomes 0
+func fixEntry(m LogData) LogData {
+	var fixed []string
+	if m.Timestamp == "" {
+		t := time.Now()
+		m.Timestamp = formatTimestamp(t)
+		m.UnixTimeStamp = unixTime(t)
+		fixed = append(fixed, "timestamp")
+	}
+	if m.Sev == "" {
+		m.Sev = levelName(m.Level)
+		fixed = append(fixed, "sev")
+	}
+	if m.Turn < 0 {
+		m.Turn = 0
+		fixed = append(fixed, "turn")
+	}
+	if len(fixed) > 0 {
+		malformedOnce.Do(func() {
+			fmt.Fprintln(os.Stderr, "snakeLogger: got a malformed log entry, filled in:", strings.Join(fixed, ", "))
+		})
+	}
+	return m
+}